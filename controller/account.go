@@ -22,6 +22,9 @@ func NewAccountController(c *AccountControllerConfig) {
 
 	api := c.R.Group("/api/v1")
 	api.GET("/account/:address/balance", accountController.GetBalance)
+	api.POST("/account/balances", accountController.GetBalances)
+	api.GET("/account/:address/tokens/:token/balance", accountController.GetTokenBalance)
+	api.GET("/account/:address/tokens/:token/allowance/:spender", accountController.GetTokenAllowance)
 	api.POST("/account", accountController.CreateAccount)
 }
 
@@ -36,6 +39,12 @@ func (s *AccountController) GetBalance(c *gin.Context) {
 		return
 	}
 
+	errorInfo = serializer.ShouldBindQuery(&request)
+	if errorInfo != nil {
+		serializer.ErrorResponse(errorInfo)
+		return
+	}
+
 	errorInfo = request.Validate(ctx)
 	if errorInfo != nil {
 		serializer.ErrorResponse(errorInfo)
@@ -51,6 +60,84 @@ func (s *AccountController) GetBalance(c *gin.Context) {
 	serializer.SuccessfulResponse(http.StatusOK, response)
 }
 
+func (s *AccountController) GetBalances(c *gin.Context) {
+	serializer := serializers.Serializer{C: c}
+	ctx := c.Request.Context()
+	var request serializers.GetBalancesRequest
+
+	errorInfo := serializer.ShouldBindJSON(&request)
+	if errorInfo != nil {
+		serializer.ErrorResponse(errorInfo)
+		return
+	}
+
+	errorInfo = request.Validate(ctx)
+	if errorInfo != nil {
+		serializer.ErrorResponse(errorInfo)
+		return
+	}
+
+	response, errInfo := s.Service.GetBalances(ctx, request)
+	if errInfo != nil {
+		serializer.ErrorResponse(errInfo)
+		return
+	}
+
+	serializer.SuccessfulResponse(http.StatusOK, response)
+}
+
+func (s *AccountController) GetTokenBalance(c *gin.Context) {
+	serializer := serializers.Serializer{C: c}
+	ctx := c.Request.Context()
+	var request serializers.GetTokenBalanceRequest
+
+	errorInfo := serializer.ShouldBindUri(&request)
+	if errorInfo != nil {
+		serializer.ErrorResponse(errorInfo)
+		return
+	}
+
+	errorInfo = request.Validate(ctx)
+	if errorInfo != nil {
+		serializer.ErrorResponse(errorInfo)
+		return
+	}
+
+	response, errInfo := s.Service.GetTokenBalance(ctx, request)
+	if errInfo != nil {
+		serializer.ErrorResponse(errInfo)
+		return
+	}
+
+	serializer.SuccessfulResponse(http.StatusOK, response)
+}
+
+func (s *AccountController) GetTokenAllowance(c *gin.Context) {
+	serializer := serializers.Serializer{C: c}
+	ctx := c.Request.Context()
+	var request serializers.GetTokenAllowanceRequest
+
+	errorInfo := serializer.ShouldBindUri(&request)
+	if errorInfo != nil {
+		serializer.ErrorResponse(errorInfo)
+		return
+	}
+
+	errorInfo = request.Validate(ctx)
+	if errorInfo != nil {
+		serializer.ErrorResponse(errorInfo)
+		return
+	}
+
+	response, errInfo := s.Service.GetTokenAllowance(ctx, request)
+	if errInfo != nil {
+		serializer.ErrorResponse(errInfo)
+		return
+	}
+
+	serializer.SuccessfulResponse(http.StatusOK, response)
+}
+
 func (s *AccountController) CreateAccount(c *gin.Context) {
 	serializer := serializers.Serializer{C: c}
 	response, errInfo := s.Service.CreateAccount()
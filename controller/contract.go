@@ -0,0 +1,177 @@
+package controller
+
+import (
+	"github.com/gin-gonic/gin"
+	"golang-ethereum-example-api/serializers"
+	"golang-ethereum-example-api/services"
+	"net/http"
+)
+
+type ContractController struct {
+	Service services.ContractService
+}
+type ContractControllerConfig struct {
+	R       *gin.Engine
+	Service services.ContractService
+}
+
+func NewContractController(c *ContractControllerConfig) {
+	contractController := &ContractController{
+		Service: c.Service,
+	}
+
+	api := c.R.Group("/api/v1")
+	api.POST("/contracts", contractController.RegisterContract)
+	api.POST("/contracts/deploy", contractController.DeployContract)
+	api.GET("/contracts/:name", contractController.GetContract)
+	api.POST("/contracts/:name/call/:method", contractController.CallContract)
+	api.POST("/contracts/:name/transact/:method", contractController.TransactContract)
+}
+
+func (s *ContractController) RegisterContract(c *gin.Context) {
+	serializer := serializers.Serializer{C: c}
+	ctx := c.Request.Context()
+	var request serializers.RegisterContractRequest
+
+	errorInfo := serializer.ShouldBindJSON(&request)
+	if errorInfo != nil {
+		serializer.ErrorResponse(errorInfo)
+		return
+	}
+
+	errorInfo = request.Validate(ctx)
+	if errorInfo != nil {
+		serializer.ErrorResponse(errorInfo)
+		return
+	}
+
+	response, errInfo := s.Service.RegisterContract(ctx, request)
+	if errInfo != nil {
+		serializer.ErrorResponse(errInfo)
+		return
+	}
+
+	serializer.SuccessfulResponse(http.StatusOK, response)
+}
+
+func (s *ContractController) GetContract(c *gin.Context) {
+	serializer := serializers.Serializer{C: c}
+	ctx := c.Request.Context()
+	var request serializers.GetContractRequest
+
+	errorInfo := serializer.ShouldBindUri(&request)
+	if errorInfo != nil {
+		serializer.ErrorResponse(errorInfo)
+		return
+	}
+
+	errorInfo = request.Validate(ctx)
+	if errorInfo != nil {
+		serializer.ErrorResponse(errorInfo)
+		return
+	}
+
+	response, errInfo := s.Service.GetContract(ctx, request)
+	if errInfo != nil {
+		serializer.ErrorResponse(errInfo)
+		return
+	}
+
+	serializer.SuccessfulResponse(http.StatusOK, response)
+}
+
+func (s *ContractController) CallContract(c *gin.Context) {
+	serializer := serializers.Serializer{C: c}
+	ctx := c.Request.Context()
+	var request serializers.CallContractRequest
+
+	errorInfo := serializer.ShouldBindUri(&request)
+	if errorInfo != nil {
+		serializer.ErrorResponse(errorInfo)
+		return
+	}
+
+	errorInfo = serializer.ShouldBindJSON(&request)
+	if errorInfo != nil {
+		serializer.ErrorResponse(errorInfo)
+		return
+	}
+
+	errorInfo = request.Validate(ctx)
+	if errorInfo != nil {
+		serializer.ErrorResponse(errorInfo)
+		return
+	}
+
+	response, errInfo := s.Service.CallContract(ctx, request)
+	if errInfo != nil {
+		serializer.ErrorResponse(errInfo)
+		return
+	}
+
+	serializer.SuccessfulResponse(http.StatusOK, response)
+}
+
+func (s *ContractController) TransactContract(c *gin.Context) {
+	serializer := serializers.Serializer{C: c}
+	ctx := c.Request.Context()
+	var request serializers.TransactContractRequest
+
+	errorInfo := serializer.ShouldBindUri(&request)
+	if errorInfo != nil {
+		serializer.ErrorResponse(errorInfo)
+		return
+	}
+
+	errorInfo = serializer.ShouldBindJSON(&request)
+	if errorInfo != nil {
+		serializer.ErrorResponse(errorInfo)
+		return
+	}
+
+	errorInfo = request.Validate(ctx)
+	if errorInfo != nil {
+		serializer.ErrorResponse(errorInfo)
+		return
+	}
+
+	response, errInfo := s.Service.TransactContract(ctx, request)
+	if errInfo != nil {
+		serializer.ErrorResponse(errInfo)
+		return
+	}
+	if request.WaitConfirmations > 0 && response.Receipt == nil {
+		serializer.SuccessfulResponse(http.StatusAccepted, response)
+		return
+	}
+	serializer.SuccessfulResponse(http.StatusOK, response)
+}
+
+func (s *ContractController) DeployContract(c *gin.Context) {
+	serializer := serializers.Serializer{C: c}
+	ctx := c.Request.Context()
+	var request serializers.DeployContractRequest
+
+	errorInfo := serializer.ShouldBindJSON(&request)
+	if errorInfo != nil {
+		serializer.ErrorResponse(errorInfo)
+		return
+	}
+
+	errorInfo = request.Validate(ctx)
+	if errorInfo != nil {
+		serializer.ErrorResponse(errorInfo)
+		return
+	}
+
+	response, errInfo := s.Service.DeployContract(ctx, request)
+	if errInfo != nil {
+		serializer.ErrorResponse(errInfo)
+		return
+	}
+	if request.WaitConfirmations > 0 && response.Receipt == nil {
+		serializer.SuccessfulResponse(http.StatusAccepted, response)
+		return
+	}
+	serializer.SuccessfulResponse(http.StatusOK, response)
+}
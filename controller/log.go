@@ -0,0 +1,51 @@
+package controller
+
+import (
+	"github.com/gin-gonic/gin"
+	"golang-ethereum-example-api/serializers"
+	"golang-ethereum-example-api/services"
+	"net/http"
+)
+
+type LogController struct {
+	Service services.LogService
+}
+type LogControllerConfig struct {
+	R       *gin.Engine
+	Service services.LogService
+}
+
+func NewLogController(c *LogControllerConfig) {
+	logController := &LogController{
+		Service: c.Service,
+	}
+
+	api := c.R.Group("/api/v1")
+	api.GET("/logs", logController.GetLogs)
+}
+
+func (s *LogController) GetLogs(c *gin.Context) {
+	serializer := serializers.Serializer{C: c}
+	ctx := c.Request.Context()
+	var request serializers.GetLogsRequest
+
+	errorInfo := serializer.ShouldBindQuery(&request)
+	if errorInfo != nil {
+		serializer.ErrorResponse(errorInfo)
+		return
+	}
+
+	errorInfo = request.Validate(ctx)
+	if errorInfo != nil {
+		serializer.ErrorResponse(errorInfo)
+		return
+	}
+
+	response, errInfo := s.Service.GetLogs(ctx, request)
+	if errInfo != nil {
+		serializer.ErrorResponse(errInfo)
+		return
+	}
+
+	serializer.SuccessfulResponse(http.StatusOK, response)
+}
@@ -0,0 +1,138 @@
+package controller
+
+import (
+	"github.com/gin-gonic/gin"
+	"golang-ethereum-example-api/serializers"
+	"golang-ethereum-example-api/services"
+	"net/http"
+)
+
+type NftController struct {
+	Service services.NftService
+}
+type NftControllerConfig struct {
+	R       *gin.Engine
+	Service services.NftService
+}
+
+func NewNftController(c *NftControllerConfig) {
+	nftController := &NftController{
+		Service: c.Service,
+	}
+
+	api := c.R.Group("/api/v1")
+	api.GET("/nft/:contract/interfaces", nftController.GetInterfaces)
+	api.GET("/nft/:contract/tokens/:tokenId/owner", nftController.GetOwner)
+	api.GET("/nft/:contract/tokens/:tokenId/uri", nftController.GetTokenURI)
+	api.GET("/account/:address/nfts/:contract/balance", nftController.GetBalance)
+}
+
+func (s *NftController) GetInterfaces(c *gin.Context) {
+	serializer := serializers.Serializer{C: c}
+	ctx := c.Request.Context()
+	var request serializers.GetNftInterfacesRequest
+
+	errorInfo := serializer.ShouldBindUri(&request)
+	if errorInfo != nil {
+		serializer.ErrorResponse(errorInfo)
+		return
+	}
+
+	errorInfo = request.Validate(ctx)
+	if errorInfo != nil {
+		serializer.ErrorResponse(errorInfo)
+		return
+	}
+
+	response, errInfo := s.Service.GetInterfaces(ctx, request)
+	if errInfo != nil {
+		serializer.ErrorResponse(errInfo)
+		return
+	}
+
+	serializer.SuccessfulResponse(http.StatusOK, response)
+}
+
+func (s *NftController) GetOwner(c *gin.Context) {
+	serializer := serializers.Serializer{C: c}
+	ctx := c.Request.Context()
+	var request serializers.GetNftTokenRequest
+
+	errorInfo := serializer.ShouldBindUri(&request)
+	if errorInfo != nil {
+		serializer.ErrorResponse(errorInfo)
+		return
+	}
+
+	errorInfo = request.Validate(ctx)
+	if errorInfo != nil {
+		serializer.ErrorResponse(errorInfo)
+		return
+	}
+
+	response, errInfo := s.Service.GetOwner(ctx, request)
+	if errInfo != nil {
+		serializer.ErrorResponse(errInfo)
+		return
+	}
+
+	serializer.SuccessfulResponse(http.StatusOK, response)
+}
+
+func (s *NftController) GetTokenURI(c *gin.Context) {
+	serializer := serializers.Serializer{C: c}
+	ctx := c.Request.Context()
+	var request serializers.GetNftTokenRequest
+
+	errorInfo := serializer.ShouldBindUri(&request)
+	if errorInfo != nil {
+		serializer.ErrorResponse(errorInfo)
+		return
+	}
+
+	errorInfo = request.Validate(ctx)
+	if errorInfo != nil {
+		serializer.ErrorResponse(errorInfo)
+		return
+	}
+
+	response, errInfo := s.Service.GetTokenURI(ctx, request)
+	if errInfo != nil {
+		serializer.ErrorResponse(errInfo)
+		return
+	}
+
+	serializer.SuccessfulResponse(http.StatusOK, response)
+}
+
+func (s *NftController) GetBalance(c *gin.Context) {
+	serializer := serializers.Serializer{C: c}
+	ctx := c.Request.Context()
+	var request serializers.GetNftBalanceRequest
+
+	errorInfo := serializer.ShouldBindUri(&request)
+	if errorInfo != nil {
+		serializer.ErrorResponse(errorInfo)
+		return
+	}
+
+	errorInfo = serializer.ShouldBindQuery(&request)
+	if errorInfo != nil {
+		serializer.ErrorResponse(errorInfo)
+		return
+	}
+
+	errorInfo = request.Validate(ctx)
+	if errorInfo != nil {
+		serializer.ErrorResponse(errorInfo)
+		return
+	}
+
+	response, errInfo := s.Service.GetBalance(ctx, request)
+	if errInfo != nil {
+		serializer.ErrorResponse(errInfo)
+		return
+	}
+
+	serializer.SuccessfulResponse(http.StatusOK, response)
+}
@@ -0,0 +1,183 @@
+package controller
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"golang-ethereum-example-api/serializers"
+	"golang-ethereum-example-api/services"
+	"io"
+	"net/http"
+	"time"
+)
+
+const (
+	streamKeepAliveInterval = 15 * time.Second
+	streamWriteWait         = 10 * time.Second
+)
+
+var upgrader = websocket.Upgrader{}
+
+type StreamController struct {
+	Service services.StreamService
+}
+type StreamControllerConfig struct {
+	R       *gin.Engine
+	Service services.StreamService
+}
+
+func NewStreamController(c *StreamControllerConfig) {
+	streamController := &StreamController{
+		Service: c.Service,
+	}
+
+	api := c.R.Group("/api/v1")
+	api.GET("/stream/heads", streamController.StreamHeads)
+	api.GET("/stream/heads/ws", streamController.StreamHeadsWebSocket)
+	api.GET("/stream/logs", streamController.StreamLogs)
+	api.GET("/stream/logs/ws", streamController.StreamLogsWebSocket)
+}
+
+func (s *StreamController) StreamHeads(c *gin.Context) {
+	serializer := serializers.Serializer{C: c}
+	ctx := c.Request.Context()
+
+	heads, errInfo := s.Service.SubscribeHeads(ctx)
+	if errInfo != nil {
+		serializer.ErrorResponse(errInfo)
+		return
+	}
+	streamEvents(c, "head", heads)
+}
+
+func (s *StreamController) StreamHeadsWebSocket(c *gin.Context) {
+	serializer := serializers.Serializer{C: c}
+	ctx := c.Request.Context()
+
+	heads, errInfo := s.Service.SubscribeHeads(ctx)
+	if errInfo != nil {
+		serializer.ErrorResponse(errInfo)
+		return
+	}
+	streamWebSocket(c, heads)
+}
+
+func (s *StreamController) StreamLogs(c *gin.Context) {
+	logs, ok := s.subscribeLogs(c)
+	if !ok {
+		return
+	}
+	streamEvents(c, "log", logs)
+}
+
+func (s *StreamController) StreamLogsWebSocket(c *gin.Context) {
+	logs, ok := s.subscribeLogs(c)
+	if !ok {
+		return
+	}
+	streamWebSocket(c, logs)
+}
+
+func (s *StreamController) subscribeLogs(c *gin.Context) (<-chan serializers.LogResponse, bool) {
+	serializer := serializers.Serializer{C: c}
+	ctx := c.Request.Context()
+	var request serializers.StreamLogsRequest
+
+	errorInfo := serializer.ShouldBindQuery(&request)
+	if errorInfo != nil {
+		serializer.ErrorResponse(errorInfo)
+		return nil, false
+	}
+
+	errorInfo = request.Validate(ctx)
+	if errorInfo != nil {
+		serializer.ErrorResponse(errorInfo)
+		return nil, false
+	}
+
+	logs, errInfo := s.Service.SubscribeLogs(ctx, request)
+	if errInfo != nil {
+		serializer.ErrorResponse(errInfo)
+		return nil, false
+	}
+	return logs, true
+}
+
+// streamEvents writes items as Server-Sent Events until the client leaves or the stream ends.
+// Comment lines are sent in between to keep idle proxies from closing the connection.
+func streamEvents[T any](c *gin.Context, event string, items <-chan T) {
+	// Streams outlive the server write timeout, which only applies to regular requests.
+	_ = http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{})
+	c.Header("Cache-Control", "no-cache")
+	c.Header("X-Accel-Buffering", "no")
+
+	ctx := c.Request.Context()
+	keepAlive := time.NewTicker(streamKeepAliveInterval)
+	defer keepAlive.Stop()
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-ctx.Done():
+			return false
+		case item, ok := <-items:
+			if !ok {
+				return false
+			}
+			c.SSEvent(event, item)
+			return true
+		case <-keepAlive.C:
+			_, err := io.WriteString(w, ": keep-alive\n\n")
+			return err == nil
+		}
+	})
+}
+
+// streamWebSocket upgrades the request and writes every item as a JSON text message. Pings
+// keep the connection alive and a missing pong closes it.
+func streamWebSocket[T any](c *gin.Context, items <-chan T) {
+	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		// Upgrade has already written the error response.
+		return
+	}
+	defer conn.Close()
+
+	pongWait := 2 * streamKeepAliveInterval
+	_ = conn.SetReadDeadline(time.Now().Add(pongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(pongWait))
+	})
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for {
+			_, _, err := conn.NextReader()
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	ctx := c.Request.Context()
+	keepAlive := time.NewTicker(streamKeepAliveInterval)
+	defer keepAlive.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-closed:
+			return
+		case item, ok := <-items:
+			if !ok {
+				_ = conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseGoingAway, "stream closed"), time.Now().Add(streamWriteWait))
+				return
+			}
+			_ = conn.SetWriteDeadline(time.Now().Add(streamWriteWait))
+			if conn.WriteJSON(item) != nil {
+				return
+			}
+		case <-keepAlive.C:
+			if conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(streamWriteWait)) != nil {
+				return
+			}
+		}
+	}
+}
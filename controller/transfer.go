@@ -1,7 +1,9 @@
 package controller
 
 import (
+	"context"
 	"github.com/gin-gonic/gin"
+	"golang-ethereum-example-api/pkg/util"
 	"golang-ethereum-example-api/serializers"
 	"golang-ethereum-example-api/services"
 	"net/http"
@@ -22,6 +24,18 @@ func NewTransferController(c *TransferControllerConfig) {
 
 	api := c.R.Group("/api/v1")
 	api.POST("/transfer/send", transferController.SendEthereum)
+	api.POST("/transfer/token", transferController.SendToken)
+	api.POST("/transfer/token/approve", transferController.ApproveToken)
+	api.POST("/transfer/nft/erc721", transferController.TransferErc721)
+	api.POST("/transfer/nft/erc1155", transferController.TransferErc1155)
+	api.POST("/transfer/nft/erc1155/batch", transferController.TransferErc1155Batch)
+	api.POST("/transfer/estimate", transferController.EstimateTransfer)
+	api.POST("/transfer/prepare", transferController.PrepareTransfer)
+	api.POST("/transfer/raw", transferController.SendRawTransaction)
+	api.GET("/transfer/:hash", transferController.GetTransaction)
+	api.POST("/transfer/:hash/speedup", transferController.SpeedUpTransaction)
+	api.POST("/transfer/:hash/cancel", transferController.CancelTransaction)
+	api.GET("/transfers", transferController.ListTransfers)
 }
 
 func (s *TransferController) SendEthereum(c *gin.Context) {
@@ -45,5 +59,325 @@ func (s *TransferController) SendEthereum(c *gin.Context) {
 		serializer.ErrorResponse(errInfo2)
 		return
 	}
+	if request.WaitConfirmations > 0 && response.Receipt == nil {
+		serializer.SuccessfulResponse(http.StatusAccepted, response)
+		return
+	}
+	serializer.SuccessfulResponse(http.StatusOK, response)
+}
+
+func (s *TransferController) SendToken(c *gin.Context) {
+	serializer := serializers.Serializer{C: c}
+	ctx := c.Request.Context()
+	var request serializers.SendTokenRequest
+	errInfo := serializer.ShouldBindJSON(&request)
+	if errInfo != nil {
+		serializer.ErrorResponse(errInfo)
+		return
+	}
+
+	errInfo = request.Validate(ctx)
+	if errInfo != nil {
+		serializer.ErrorResponse(errInfo)
+		return
+	}
+
+	response, errInfo := s.Service.SendToken(ctx, request)
+	if errInfo != nil {
+		serializer.ErrorResponse(errInfo)
+		return
+	}
+	if request.WaitConfirmations > 0 && response.Receipt == nil {
+		serializer.SuccessfulResponse(http.StatusAccepted, response)
+		return
+	}
+	serializer.SuccessfulResponse(http.StatusOK, response)
+}
+
+func (s *TransferController) ApproveToken(c *gin.Context) {
+	serializer := serializers.Serializer{C: c}
+	ctx := c.Request.Context()
+	var request serializers.ApproveTokenRequest
+	errInfo := serializer.ShouldBindJSON(&request)
+	if errInfo != nil {
+		serializer.ErrorResponse(errInfo)
+		return
+	}
+
+	errInfo = request.Validate(ctx)
+	if errInfo != nil {
+		serializer.ErrorResponse(errInfo)
+		return
+	}
+
+	response, errInfo := s.Service.ApproveToken(ctx, request)
+	if errInfo != nil {
+		serializer.ErrorResponse(errInfo)
+		return
+	}
+	// The allowance reset of a safe approve is still pending, the approval itself was not sent.
+	if request.WaitConfirmations > 0 && response.Receipt == nil {
+		serializer.SuccessfulResponse(http.StatusAccepted, response)
+		return
+	}
+	serializer.SuccessfulResponse(http.StatusOK, response)
+}
+
+func (s *TransferController) TransferErc721(c *gin.Context) {
+	serializer := serializers.Serializer{C: c}
+	ctx := c.Request.Context()
+	var request serializers.TransferErc721Request
+	errInfo := serializer.ShouldBindJSON(&request)
+	if errInfo != nil {
+		serializer.ErrorResponse(errInfo)
+		return
+	}
+
+	errInfo = request.Validate(ctx)
+	if errInfo != nil {
+		serializer.ErrorResponse(errInfo)
+		return
+	}
+
+	response, errInfo := s.Service.TransferErc721(ctx, request)
+	if errInfo != nil {
+		serializer.ErrorResponse(errInfo)
+		return
+	}
+	if request.WaitConfirmations > 0 && response.Receipt == nil {
+		serializer.SuccessfulResponse(http.StatusAccepted, response)
+		return
+	}
+	serializer.SuccessfulResponse(http.StatusOK, response)
+}
+
+func (s *TransferController) TransferErc1155(c *gin.Context) {
+	serializer := serializers.Serializer{C: c}
+	ctx := c.Request.Context()
+	var request serializers.TransferErc1155Request
+	errInfo := serializer.ShouldBindJSON(&request)
+	if errInfo != nil {
+		serializer.ErrorResponse(errInfo)
+		return
+	}
+
+	errInfo = request.Validate(ctx)
+	if errInfo != nil {
+		serializer.ErrorResponse(errInfo)
+		return
+	}
+
+	response, errInfo := s.Service.TransferErc1155(ctx, request)
+	if errInfo != nil {
+		serializer.ErrorResponse(errInfo)
+		return
+	}
+	if request.WaitConfirmations > 0 && response.Receipt == nil {
+		serializer.SuccessfulResponse(http.StatusAccepted, response)
+		return
+	}
+	serializer.SuccessfulResponse(http.StatusOK, response)
+}
+
+func (s *TransferController) TransferErc1155Batch(c *gin.Context) {
+	serializer := serializers.Serializer{C: c}
+	ctx := c.Request.Context()
+	var request serializers.TransferErc1155BatchRequest
+	errInfo := serializer.ShouldBindJSON(&request)
+	if errInfo != nil {
+		serializer.ErrorResponse(errInfo)
+		return
+	}
+
+	errInfo = request.Validate(ctx)
+	if errInfo != nil {
+		serializer.ErrorResponse(errInfo)
+		return
+	}
+
+	response, errInfo := s.Service.TransferErc1155Batch(ctx, request)
+	if errInfo != nil {
+		serializer.ErrorResponse(errInfo)
+		return
+	}
+	if request.WaitConfirmations > 0 && response.Receipt == nil {
+		serializer.SuccessfulResponse(http.StatusAccepted, response)
+		return
+	}
+	serializer.SuccessfulResponse(http.StatusOK, response)
+}
+
+func (s *TransferController) EstimateTransfer(c *gin.Context) {
+	serializer := serializers.Serializer{C: c}
+	ctx := c.Request.Context()
+	var request serializers.EstimateTransferRequest
+	errInfo := serializer.ShouldBindJSON(&request)
+	if errInfo != nil {
+		serializer.ErrorResponse(errInfo)
+		return
+	}
+
+	errInfo = request.Validate(ctx)
+	if errInfo != nil {
+		serializer.ErrorResponse(errInfo)
+		return
+	}
+
+	response, errInfo := s.Service.EstimateTransfer(ctx, request)
+	if errInfo != nil {
+		serializer.ErrorResponse(errInfo)
+		return
+	}
+	serializer.SuccessfulResponse(http.StatusOK, response)
+}
+
+func (s *TransferController) GetTransaction(c *gin.Context) {
+	serializer := serializers.Serializer{C: c}
+	ctx := c.Request.Context()
+	var request serializers.GetTransactionRequest
+
+	errorInfo := serializer.ShouldBindUri(&request)
+	if errorInfo != nil {
+		serializer.ErrorResponse(errorInfo)
+		return
+	}
+
+	errorInfo = request.Validate(ctx)
+	if errorInfo != nil {
+		serializer.ErrorResponse(errorInfo)
+		return
+	}
+
+	response, errInfo := s.Service.GetTransaction(ctx, request)
+	if errInfo != nil {
+		serializer.ErrorResponse(errInfo)
+		return
+	}
+
+	serializer.SuccessfulResponse(http.StatusOK, response)
+}
+
+func (s *TransferController) ListTransfers(c *gin.Context) {
+	serializer := serializers.Serializer{C: c}
+	ctx := c.Request.Context()
+	var request serializers.ListTransfersRequest
+
+	errorInfo := serializer.ShouldBindQuery(&request)
+	if errorInfo != nil {
+		serializer.ErrorResponse(errorInfo)
+		return
+	}
+
+	errorInfo = request.Validate(ctx)
+	if errorInfo != nil {
+		serializer.ErrorResponse(errorInfo)
+		return
+	}
+
+	response, errInfo := s.Service.ListTransfers(ctx, request)
+	if errInfo != nil {
+		serializer.ErrorResponse(errInfo)
+		return
+	}
+
+	serializer.SuccessfulResponse(http.StatusOK, response)
+}
+
+func (s *TransferController) SpeedUpTransaction(c *gin.Context) {
+	s.replaceTransaction(c, s.Service.SpeedUpTransaction)
+}
+
+func (s *TransferController) CancelTransaction(c *gin.Context) {
+	s.replaceTransaction(c, s.Service.CancelTransaction)
+}
+
+func (s *TransferController) replaceTransaction(c *gin.Context, replace func(ctx context.Context, request serializers.ReplaceTransactionRequest) (*serializers.ReplaceTransactionResponse, *util.ErrorInfo)) {
+	serializer := serializers.Serializer{C: c}
+	ctx := c.Request.Context()
+	var request serializers.ReplaceTransactionRequest
+
+	errorInfo := serializer.ShouldBindUri(&request)
+	if errorInfo != nil {
+		serializer.ErrorResponse(errorInfo)
+		return
+	}
+
+	// Every field of the body is optional.
+	if c.Request.ContentLength != 0 {
+		errorInfo = serializer.ShouldBindJSON(&request)
+		if errorInfo != nil {
+			serializer.ErrorResponse(errorInfo)
+			return
+		}
+	}
+
+	errorInfo = request.Validate(ctx)
+	if errorInfo != nil {
+		serializer.ErrorResponse(errorInfo)
+		return
+	}
+
+	response, errInfo := replace(ctx, request)
+	if errInfo != nil {
+		serializer.ErrorResponse(errInfo)
+		return
+	}
+	if request.WaitConfirmations > 0 && response.Receipt == nil {
+		serializer.SuccessfulResponse(http.StatusAccepted, response)
+		return
+	}
+	serializer.SuccessfulResponse(http.StatusOK, response)
+}
+
+func (s *TransferController) PrepareTransfer(c *gin.Context) {
+	serializer := serializers.Serializer{C: c}
+	ctx := c.Request.Context()
+	var request serializers.PrepareTransferRequest
+	errInfo := serializer.ShouldBindJSON(&request)
+	if errInfo != nil {
+		serializer.ErrorResponse(errInfo)
+		return
+	}
+
+	errInfo = request.Validate(ctx)
+	if errInfo != nil {
+		serializer.ErrorResponse(errInfo)
+		return
+	}
+
+	response, errInfo := s.Service.PrepareTransfer(ctx, request)
+	if errInfo != nil {
+		serializer.ErrorResponse(errInfo)
+		return
+	}
+	serializer.SuccessfulResponse(http.StatusOK, response)
+}
+
+func (s *TransferController) SendRawTransaction(c *gin.Context) {
+	serializer := serializers.Serializer{C: c}
+	ctx := c.Request.Context()
+	var request serializers.SendRawTransactionRequest
+
+	errorInfo := serializer.ShouldBindJSON(&request)
+	if errorInfo != nil {
+		serializer.ErrorResponse(errorInfo)
+		return
+	}
+
+	errorInfo = request.Validate(ctx)
+	if errorInfo != nil {
+		serializer.ErrorResponse(errorInfo)
+		return
+	}
+
+	response, errInfo := s.Service.SendRawTransaction(ctx, request)
+	if errInfo != nil {
+		serializer.ErrorResponse(errInfo)
+		return
+	}
+	if request.WaitConfirmations > 0 && response.Receipt == nil {
+		serializer.SuccessfulResponse(http.StatusAccepted, response)
+		return
+	}
 	serializer.SuccessfulResponse(http.StatusOK, response)
 }
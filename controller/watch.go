@@ -0,0 +1,101 @@
+package controller
+
+import (
+	"github.com/gin-gonic/gin"
+	"golang-ethereum-example-api/serializers"
+	"golang-ethereum-example-api/services"
+	"net/http"
+)
+
+type WatchController struct {
+	Service services.WatchService
+}
+type WatchControllerConfig struct {
+	R       *gin.Engine
+	Service services.WatchService
+}
+
+func NewWatchController(c *WatchControllerConfig) {
+	watchController := &WatchController{
+		Service: c.Service,
+	}
+
+	api := c.R.Group("/api/v1")
+	api.GET("/watch", watchController.ListWatches)
+	api.POST("/watch/:address", watchController.WatchAddress)
+	api.DELETE("/watch/:address", watchController.UnwatchAddress)
+}
+
+func (s *WatchController) WatchAddress(c *gin.Context) {
+	serializer := serializers.Serializer{C: c}
+	ctx := c.Request.Context()
+	var request serializers.WatchAddressRequest
+
+	errorInfo := serializer.ShouldBindUri(&request)
+	if errorInfo != nil {
+		serializer.ErrorResponse(errorInfo)
+		return
+	}
+
+	// The body is optional, addresses without a webhookUrl use WATCH_WEBHOOK_URL.
+	if c.Request.ContentLength != 0 {
+		errorInfo = serializer.ShouldBindJSON(&request)
+		if errorInfo != nil {
+			serializer.ErrorResponse(errorInfo)
+			return
+		}
+	}
+
+	errorInfo = request.Validate(ctx)
+	if errorInfo != nil {
+		serializer.ErrorResponse(errorInfo)
+		return
+	}
+
+	response, errInfo := s.Service.WatchAddress(ctx, request)
+	if errInfo != nil {
+		serializer.ErrorResponse(errInfo)
+		return
+	}
+
+	serializer.SuccessfulResponse(http.StatusOK, response)
+}
+
+func (s *WatchController) UnwatchAddress(c *gin.Context) {
+	serializer := serializers.Serializer{C: c}
+	ctx := c.Request.Context()
+	var request serializers.UnwatchAddressRequest
+
+	errorInfo := serializer.ShouldBindUri(&request)
+	if errorInfo != nil {
+		serializer.ErrorResponse(errorInfo)
+		return
+	}
+
+	errorInfo = request.Validate(ctx)
+	if errorInfo != nil {
+		serializer.ErrorResponse(errorInfo)
+		return
+	}
+
+	response, errInfo := s.Service.UnwatchAddress(ctx, request)
+	if errInfo != nil {
+		serializer.ErrorResponse(errInfo)
+		return
+	}
+
+	serializer.SuccessfulResponse(http.StatusOK, response)
+}
+
+func (s *WatchController) ListWatches(c *gin.Context) {
+	serializer := serializers.Serializer{C: c}
+	ctx := c.Request.Context()
+
+	response, errInfo := s.Service.ListWatches(ctx)
+	if errInfo != nil {
+		serializer.ErrorResponse(errInfo)
+		return
+	}
+
+	serializer.SuccessfulResponse(http.StatusOK, response)
+}
@@ -2,15 +2,19 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 	ethereumClient "golang-ethereum-example-api/pkg/geth_client"
 	"golang-ethereum-example-api/pkg/logging"
 	"golang-ethereum-example-api/pkg/settings"
+	"golang-ethereum-example-api/pkg/signer"
+	"golang-ethereum-example-api/pkg/wallet"
 	"golang-ethereum-example-api/routers"
 	"io"
 	"log"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
@@ -29,6 +33,8 @@ func init() {
 	}
 	logging.Setup(logConfig)
 	ethereumClient.Setup(settings.EthereumClientSettings, logging.GetLogger())
+	wallet.Setup(settings.WalletSettings)
+	signer.Setup(settings.WalletSettings, wallet.GetKeyStore(), logging.GetLogger())
 }
 
 func main() {
@@ -38,19 +44,28 @@ func main() {
 		gin.DefaultWriter = io.Discard
 	}
 
-	router := routers.BuildRouter()
+	router, workers := routers.BuildRouter()
 	readTimeout := settings.ServerSettings.ReadTimeout
 	writeTimeout := settings.ServerSettings.WriteTimeout
 	endPoint := fmt.Sprintf(":%d", settings.ServerSettings.HttpPort)
+	// Request contexts derive from baseCtx, so cancelling it on shutdown ends open streams.
+	baseCtx, cancelBaseCtx := context.WithCancel(context.Background())
 	server := &http.Server{
 		Addr:           endPoint,
 		Handler:        router,
 		ReadTimeout:    readTimeout,
 		WriteTimeout:   writeTimeout,
 		MaxHeaderBytes: 1 << 20,
+		BaseContext: func(net.Listener) context.Context {
+			return baseCtx
+		},
+	}
+	server.RegisterOnShutdown(cancelBaseCtx)
+	for _, worker := range workers {
+		worker.Start()
 	}
 	go func() {
-		if err := server.ListenAndServe(); err != nil {
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
 			log.Fatal("Server failed to start: ", err)
 		}
 	}()
@@ -72,4 +87,9 @@ func main() {
 	if err := server.Shutdown(ctx); err != nil {
 		logger.Error("Server shutdown error", zap.Error(err))
 	}
+	for _, worker := range workers {
+		if err := worker.Stop(ctx); err != nil {
+			logger.Error("Worker shutdown error", zap.Error(err))
+		}
+	}
 }
@@ -0,0 +1,144 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"golang-ethereum-example-api/pkg/logging"
+	"golang-ethereum-example-api/pkg/util"
+	"golang-ethereum-example-api/serializers"
+	"io"
+	"net/http"
+)
+
+const (
+	IdempotencyKeyHeader     = "Idempotency-Key"
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+	maxIdempotencyKeyLength  = 255
+)
+
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *responseRecorder) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// Idempotency makes state changing requests that carry an Idempotency-Key header safe to
+// retry. The response to the first request is stored and replayed for retries with the same
+// key, method, path and body. Reusing a key for a different request, or retrying before the
+// first request has finished, is answered with 409. Bodies are buffered up to maxBodyBytes.
+func Idempotency(store IdempotencyStore, maxBodyBytes int64, logger *logging.LogWrapper) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if key == "" || !isStateChanging(c.Request.Method) {
+			c.Next()
+			return
+		}
+		serializer := serializers.Serializer{C: c}
+		if len(key) > maxIdempotencyKeyLength {
+			serializer.ErrorResponse(&util.ErrorInfo{
+				HttpCode: http.StatusBadRequest,
+				Message:  util.InvalidIdempotencyKeyErrorMessage,
+				Err:      errors.New("idempotency key is too long"),
+			})
+			c.Abort()
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxBodyBytes))
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			serializer.ErrorResponse(&util.ErrorInfo{
+				HttpCode: http.StatusRequestEntityTooLarge,
+				Message:  util.RequestTooLargeErrorMessage,
+				Err:      err,
+			})
+			c.Abort()
+			return
+		}
+		if err != nil {
+			serializer.ErrorResponse(&util.ErrorInfo{
+				HttpCode: http.StatusBadRequest,
+				Message:  util.BindingErrorMessage,
+				Err:      err,
+			})
+			c.Abort()
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		fingerprint := requestFingerprint(c.Request.Method, c.Request.URL.Path, body)
+		entry, created, err := store.Begin(key, fingerprint)
+		if err != nil {
+			logger.Error("Idempotency begin request error", zap.Error(err))
+			serializer.ErrorResponse(&util.ErrorInfo{
+				HttpCode: http.StatusInternalServerError,
+				Message:  util.InternalServiceErrorMessage,
+				Err:      err,
+			})
+			c.Abort()
+			return
+		}
+
+		if !created {
+			switch {
+			case entry.Fingerprint != fingerprint:
+				serializer.ErrorResponse(&util.ErrorInfo{
+					HttpCode: http.StatusConflict,
+					Message:  util.IdempotencyKeyReusedErrorMessage,
+					Detail:   "the key was used for a different request",
+					Err:      errors.New("idempotency key reused"),
+				})
+			case !entry.Completed():
+				serializer.ErrorResponse(&util.ErrorInfo{
+					HttpCode: http.StatusConflict,
+					Message:  util.RequestInProgressErrorMessage,
+					Detail:   "the first request with this key has not finished",
+					Err:      errors.New("request in progress"),
+				})
+			default:
+				c.Header(IdempotentReplayedHeader, "true")
+				c.Data(entry.Status, entry.ContentType, entry.Body)
+			}
+			c.Abort()
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		c.Next()
+
+		err = store.Complete(key, recorder.Status(), recorder.Header().Get("Content-Type"), recorder.body.Bytes())
+		if err != nil {
+			logger.Error("Idempotency saving response error", zap.Error(err), zap.String("key", key))
+		}
+	}
+}
+
+func isStateChanging(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+func requestFingerprint(method string, path string, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(method + " " + path + "\n"))
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
@@ -0,0 +1,203 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"go.etcd.io/bbolt"
+	"go.uber.org/zap"
+	"golang-ethereum-example-api/pkg/logging"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	idempotencyFile          = "idempotency.db"
+	idempotencyPurgeInterval = 10 * time.Minute
+)
+
+var idempotencyBucket = []byte("keys")
+
+// IdempotencyEntry is the stored outcome of the first request made with a key. Entries
+// without a status are still being handled, or were interrupted by a restart.
+type IdempotencyEntry struct {
+	Fingerprint string    `json:"fingerprint"`
+	Status      int       `json:"status,omitempty"`
+	ContentType string    `json:"contentType,omitempty"`
+	Body        []byte    `json:"body,omitempty"`
+	StartedAt   time.Time `json:"startedAt"`
+	ExpiresAt   time.Time `json:"expiresAt"`
+}
+
+func (e *IdempotencyEntry) Completed() bool {
+	return e.Status != 0
+}
+
+// IdempotencyStore persists idempotency keys so retries are answered consistently across
+// restarts. Start and Stop run the purge of expired keys and close the store.
+type IdempotencyStore interface {
+	Begin(key string, fingerprint string) (*IdempotencyEntry, bool, error)
+	Complete(key string, status int, contentType string, body []byte) error
+	Start()
+	Stop(ctx context.Context) error
+}
+
+type boltIdempotencyStore struct {
+	db     *bbolt.DB
+	ttl    time.Duration
+	lease  time.Duration
+	logger *logging.LogWrapper
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewIdempotencyStore keeps keys for ttl. A request still unfinished after lease is assumed
+// to have been interrupted and its key may be claimed again.
+func NewIdempotencyStore(dir string, ttl time.Duration, lease time.Duration, logger *logging.LogWrapper) (IdempotencyStore, error) {
+	err := os.MkdirAll(dir, 0700)
+	if err != nil {
+		return nil, err
+	}
+	db, err := bbolt.Open(filepath.Join(dir, idempotencyFile), 0600, &bbolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, err
+	}
+	err = db.Update(func(tx *bbolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(idempotencyBucket)
+		return err
+	})
+	if err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+	return &boltIdempotencyStore{db: db, ttl: ttl, lease: lease, logger: logger}, nil
+}
+
+// Begin claims key for a new request. When the key is already in use the existing entry is
+// returned and the bool is false. A retry of a request left unfinished past the lease takes
+// the key over.
+func (s *boltIdempotencyStore) Begin(key string, fingerprint string) (*IdempotencyEntry, bool, error) {
+	var existing *IdempotencyEntry
+	err := s.db.Update(func(tx *bbolt.Tx) error {
+		bucket := tx.Bucket(idempotencyBucket)
+		entry, err := getIdempotencyEntry(bucket, key)
+		if err != nil {
+			return err
+		}
+		now := time.Now()
+		if entry != nil && now.Before(entry.ExpiresAt) {
+			abandoned := !entry.Completed() && entry.Fingerprint == fingerprint && now.Sub(entry.StartedAt) >= s.lease
+			if !abandoned {
+				existing = entry
+				return nil
+			}
+			s.logger.Warn("Idempotency taking over abandoned key", zap.String("key", key), zap.Time("startedAt", entry.StartedAt))
+		}
+		return putIdempotencyEntry(bucket, key, &IdempotencyEntry{
+			Fingerprint: fingerprint,
+			StartedAt:   now,
+			ExpiresAt:   now.Add(s.ttl),
+		})
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	return existing, existing == nil, nil
+}
+
+func (s *boltIdempotencyStore) Complete(key string, status int, contentType string, body []byte) error {
+	return s.db.Update(func(tx *bbolt.Tx) error {
+		bucket := tx.Bucket(idempotencyBucket)
+		entry, err := getIdempotencyEntry(bucket, key)
+		if err != nil || entry == nil {
+			return err
+		}
+		entry.Status = status
+		entry.ContentType = contentType
+		entry.Body = body
+		return putIdempotencyEntry(bucket, key, entry)
+	})
+}
+
+func (s *boltIdempotencyStore) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	s.done = make(chan struct{})
+	go func() {
+		defer close(s.done)
+		ticker := time.NewTicker(idempotencyPurgeInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				err := s.purge()
+				if err != nil {
+					s.logger.Error("Idempotency store purge error", zap.Error(err))
+				}
+			}
+		}
+	}()
+}
+
+func (s *boltIdempotencyStore) Stop(ctx context.Context) error {
+	if s.cancel != nil {
+		s.cancel()
+		select {
+		case <-s.done:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return s.db.Close()
+}
+
+func (s *boltIdempotencyStore) purge() error {
+	now := time.Now()
+	return s.db.Update(func(tx *bbolt.Tx) error {
+		bucket := tx.Bucket(idempotencyBucket)
+		var expired [][]byte
+		err := bucket.ForEach(func(k, v []byte) error {
+			var entry IdempotencyEntry
+			err := json.Unmarshal(v, &entry)
+			if err != nil || now.After(entry.ExpiresAt) {
+				expired = append(expired, bytes.Clone(k))
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		for _, k := range expired {
+			err = bucket.Delete(k)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func getIdempotencyEntry(bucket *bbolt.Bucket, key string) (*IdempotencyEntry, error) {
+	data := bucket.Get([]byte(key))
+	if data == nil {
+		return nil, nil
+	}
+	var entry IdempotencyEntry
+	err := json.Unmarshal(data, &entry)
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+func putIdempotencyEntry(bucket *bbolt.Bucket, key string, entry *IdempotencyEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	return bucket.Put([]byte(key), data)
+}
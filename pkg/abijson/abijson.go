@@ -0,0 +1,257 @@
+package abijson
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"math/big"
+	"reflect"
+	"strconv"
+)
+
+var bigIntType = reflect.TypeOf(new(big.Int))
+
+// ParseArguments converts positional JSON values into the Go values abi.Arguments.Pack expects.
+// Integers may be JSON numbers or decimal/0x strings, bytes are 0x hex strings and tuples are
+// objects keyed by component name or positional arrays.
+func ParseArguments(arguments abi.Arguments, values []json.RawMessage) ([]interface{}, error) {
+	if len(arguments) != len(values) {
+		return nil, fmt.Errorf("expected %d arguments, got %d", len(arguments), len(values))
+	}
+	parsed := make([]interface{}, len(arguments))
+	for i, argument := range arguments {
+		value, err := parseValue(argument.Type, values[i])
+		if err != nil {
+			return nil, fmt.Errorf("argument %d (%s): %w", i, argumentName(argument, i), err)
+		}
+		parsed[i] = value.Interface()
+	}
+	return parsed, nil
+}
+
+// FormatValues renders unpacked values as JSON friendly data. Integers are rendered as decimal
+// strings so no precision is lost, and the result is an object when every argument is named.
+func FormatValues(arguments abi.Arguments, values []interface{}) interface{} {
+	named := len(arguments) > 0
+	for _, argument := range arguments {
+		if argument.Name == "" {
+			named = false
+		}
+	}
+	if named {
+		result := make(map[string]interface{}, len(arguments))
+		for i, argument := range arguments {
+			result[argument.Name] = formatValue(argument.Type, reflect.ValueOf(values[i]))
+		}
+		return result
+	}
+	result := make([]interface{}, len(values))
+	for i, value := range values {
+		result[i] = formatValue(arguments[i].Type, reflect.ValueOf(value))
+	}
+	return result
+}
+
+func argumentName(argument abi.Argument, index int) string {
+	if argument.Name != "" {
+		return argument.Name
+	}
+	return strconv.Itoa(index)
+}
+
+func parseValue(t abi.Type, raw json.RawMessage) (reflect.Value, error) {
+	goType := t.GetType()
+	switch t.T {
+	case abi.IntTy, abi.UintTy:
+		return parseInteger(t, goType, raw)
+	case abi.BoolTy:
+		var b bool
+		if err := json.Unmarshal(raw, &b); err != nil {
+			return reflect.Value{}, fmt.Errorf("expected a boolean")
+		}
+		return reflect.ValueOf(b), nil
+	case abi.StringTy:
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return reflect.Value{}, fmt.Errorf("expected a string")
+		}
+		return reflect.ValueOf(s), nil
+	case abi.AddressTy:
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil || !common.IsHexAddress(s) || len(s) != 42 {
+			return reflect.Value{}, fmt.Errorf("expected a 0x prefixed address")
+		}
+		return reflect.ValueOf(common.HexToAddress(s)), nil
+	case abi.BytesTy:
+		data, err := parseBytes(raw)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		return reflect.ValueOf(data), nil
+	case abi.FixedBytesTy:
+		data, err := parseBytes(raw)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		if len(data) != t.Size {
+			return reflect.Value{}, fmt.Errorf("expected %d bytes, got %d", t.Size, len(data))
+		}
+		value := reflect.New(goType).Elem()
+		reflect.Copy(value, reflect.ValueOf(data))
+		return value, nil
+	case abi.SliceTy, abi.ArrayTy:
+		var items []json.RawMessage
+		if err := json.Unmarshal(raw, &items); err != nil {
+			return reflect.Value{}, fmt.Errorf("expected an array")
+		}
+		var value reflect.Value
+		if t.T == abi.SliceTy {
+			value = reflect.MakeSlice(goType, len(items), len(items))
+		} else {
+			if len(items) != t.Size {
+				return reflect.Value{}, fmt.Errorf("expected %d items, got %d", t.Size, len(items))
+			}
+			value = reflect.New(goType).Elem()
+		}
+		for i, item := range items {
+			elem, err := parseValue(*t.Elem, item)
+			if err != nil {
+				return reflect.Value{}, fmt.Errorf("item %d: %w", i, err)
+			}
+			value.Index(i).Set(elem)
+		}
+		return value, nil
+	case abi.TupleTy:
+		return parseTuple(t, goType, raw)
+	default:
+		return reflect.Value{}, fmt.Errorf("unsupported type %s", t.String())
+	}
+}
+
+func parseInteger(t abi.Type, goType reflect.Type, raw json.RawMessage) (reflect.Value, error) {
+	var text string
+	if len(raw) > 0 && raw[0] == '"' {
+		if err := json.Unmarshal(raw, &text); err != nil {
+			return reflect.Value{}, err
+		}
+	} else {
+		text = string(raw)
+	}
+	n, ok := new(big.Int).SetString(text, 0)
+	if !ok {
+		return reflect.Value{}, fmt.Errorf("expected an integer")
+	}
+
+	if !inRange(t, n) {
+		return reflect.Value{}, fmt.Errorf("%s out of range for %s", n, t.String())
+	}
+
+	if goType == bigIntType {
+		return reflect.ValueOf(n), nil
+	}
+	value := reflect.New(goType).Elem()
+	if t.T == abi.UintTy {
+		value.SetUint(n.Uint64())
+	} else {
+		value.SetInt(n.Int64())
+	}
+	return value, nil
+}
+
+func inRange(t abi.Type, n *big.Int) bool {
+	if t.T == abi.UintTy {
+		return n.Sign() >= 0 && n.BitLen() <= t.Size
+	}
+	bound := new(big.Int).Lsh(common.Big1, uint(t.Size-1))
+	return n.Cmp(bound) < 0 && n.Cmp(new(big.Int).Neg(bound)) >= 0
+}
+
+func parseBytes(raw json.RawMessage) ([]byte, error) {
+	var s string
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return nil, fmt.Errorf("expected a 0x prefixed hex string")
+	}
+	data, err := hexutil.Decode(s)
+	if err != nil {
+		return nil, fmt.Errorf("expected a 0x prefixed hex string: %w", err)
+	}
+	return data, nil
+}
+
+func parseTuple(t abi.Type, goType reflect.Type, raw json.RawMessage) (reflect.Value, error) {
+	items := make([]json.RawMessage, len(t.TupleElems))
+	if bytes.HasPrefix(bytes.TrimSpace(raw), []byte("[")) {
+		var positional []json.RawMessage
+		if err := json.Unmarshal(raw, &positional); err != nil {
+			return reflect.Value{}, fmt.Errorf("expected an object or array")
+		}
+		if len(positional) != len(items) {
+			return reflect.Value{}, fmt.Errorf("expected %d components, got %d", len(items), len(positional))
+		}
+		copy(items, positional)
+	} else {
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &fields); err != nil {
+			return reflect.Value{}, fmt.Errorf("expected an object or array")
+		}
+		for i, name := range t.TupleRawNames {
+			item, ok := fields[name]
+			if !ok {
+				return reflect.Value{}, fmt.Errorf("missing component %s", name)
+			}
+			items[i] = item
+		}
+	}
+
+	value := reflect.New(goType).Elem()
+	for i, elem := range t.TupleElems {
+		field, err := parseValue(*elem, items[i])
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("component %s: %w", t.TupleRawNames[i], err)
+		}
+		value.Field(i).Set(field)
+	}
+	return value, nil
+}
+
+func formatValue(t abi.Type, value reflect.Value) interface{} {
+	if value.Kind() == reflect.Ptr && value.IsNil() {
+		return nil
+	}
+	switch t.T {
+	case abi.IntTy, abi.UintTy:
+		if n, ok := value.Interface().(*big.Int); ok {
+			return n.String()
+		}
+		return fmt.Sprint(value.Interface())
+	case abi.AddressTy:
+		return value.Interface().(common.Address).Hex()
+	case abi.BytesTy:
+		return hexutil.Encode(value.Bytes())
+	case abi.FixedBytesTy, abi.FunctionTy:
+		data := make([]byte, value.Len())
+		reflect.Copy(reflect.ValueOf(data), value)
+		return hexutil.Encode(data)
+	case abi.SliceTy, abi.ArrayTy:
+		items := make([]interface{}, value.Len())
+		for i := range items {
+			items[i] = formatValue(*t.Elem, value.Index(i))
+		}
+		return items
+	case abi.TupleTy:
+		fields := make(map[string]interface{}, len(t.TupleElems))
+		for i, elem := range t.TupleElems {
+			name := t.TupleRawNames[i]
+			if name == "" {
+				name = strconv.Itoa(i)
+			}
+			fields[name] = formatValue(*elem, value.Field(i))
+		}
+		return fields
+	default:
+		return value.Interface()
+	}
+}
@@ -1,13 +1,18 @@
 package geth_client
 
 import (
+	"context"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"go.uber.org/zap"
 	"golang-ethereum-example-api/pkg/logging"
 	"golang-ethereum-example-api/pkg/settings"
+	"math/big"
+	"time"
 )
 
 var client *ethclient.Client
+var chainID *big.Int
+var wsUrl string
 
 func Setup(ethereumClient *settings.EthereumClient, logger *logging.LogWrapper) {
 	c, err := ethclient.Dial(ethereumClient.Url)
@@ -15,8 +20,45 @@ func Setup(ethereumClient *settings.EthereumClient, logger *logging.LogWrapper)
 		logger.Fatal("Failed to connect to the Ethereum client", zap.Error(err))
 	}
 	client = c
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	id, err := resolveChainID(ctx, c, logger)
+	if err != nil {
+		logger.Fatal("Failed to resolve the chain id", zap.Error(err))
+	}
+	if ethereumClient.ChainID != 0 && id.Cmp(new(big.Int).SetUint64(ethereumClient.ChainID)) != 0 {
+		logger.Fatal("Configured chain id does not match the node",
+			zap.Uint64("configuredChainID", ethereumClient.ChainID), zap.String("nodeChainID", id.String()))
+	}
+	chainID = id
+	wsUrl = ethereumClient.WsUrl
+}
+
+// resolveChainID prefers eth_chainId and only falls back to net_version for nodes that predate it.
+func resolveChainID(ctx context.Context, c *ethclient.Client, logger *logging.LogWrapper) (*big.Int, error) {
+	id, err := c.ChainID(ctx)
+	if err == nil {
+		return id, nil
+	}
+	logger.Warn("eth_chainId is not available, falling back to net_version", zap.Error(err))
+	return c.NetworkID(ctx)
 }
 
 func GetClient() *ethclient.Client {
 	return client
 }
+
+func GetChainID() *big.Int {
+	return chainID
+}
+
+// DialWebSocket opens a new connection to ETHEREUM_WS_URL. Subscriptions own their connection
+// so that they can reconnect independently of the HTTP client.
+func DialWebSocket(ctx context.Context) (*ethclient.Client, error) {
+	return ethclient.DialContext(ctx, wsUrl)
+}
+
+func WebSocketEnabled() bool {
+	return wsUrl != ""
+}
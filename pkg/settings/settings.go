@@ -4,6 +4,7 @@ import (
 	"log"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/go-playground/validator/v10"
@@ -29,12 +30,65 @@ type Server struct {
 var ServerSettings = &Server{}
 
 type EthereumClient struct {
-	Url      string `validate:"required"`
-	GasLimit uint64 `validate:"required"`
+	Url                 string `validate:"required"`
+	WsUrl               string `validate:"omitempty,url"`
+	GasLimit            uint64 `validate:"required"`
+	ChainID             uint64
+	NonceResyncInterval time.Duration `validate:"required"`
+	GasMultiplier       float64       `validate:"gte=1"`
+	MaxBatchSize        int           `validate:"required,min=1"`
+	LogChunkSize        uint64        `validate:"required,min=1"`
+	LogMaxScanBlocks    uint64        `validate:"required,min=1"`
 }
 
 var EthereumClientSettings = &EthereumClient{}
 
+type Wallet struct {
+	Signer             string   `validate:"required,oneof=keystore clef raw"`
+	KeystoreDir        string   `validate:"required_if=Signer keystore"`
+	Passphrase         string   `validate:"required_if=Signer keystore"`
+	ClefUrl            string   `validate:"required_if=Signer clef"`
+	RawPrivateKeys     []string `validate:"required_if=Signer raw"`
+	AllowRawPrivateKey bool
+}
+
+var WalletSettings = &Wallet{}
+
+type Contracts struct {
+	RegistryDir string `validate:"required"`
+}
+
+var ContractsSettings = &Contracts{}
+
+type Watch struct {
+	DataDir       string        `validate:"required"`
+	WebhookUrl    string        `validate:"omitempty,url"`
+	WebhookSecret string        `validate:"required"`
+	Confirmations uint64        `validate:"required,min=1"`
+	PollInterval  time.Duration `validate:"required"`
+	MaxRetries    int           `validate:"min=0"`
+}
+
+var WatchSettings = &Watch{}
+
+type Ledger struct {
+	DataDir       string        `validate:"required"`
+	TrackInterval time.Duration `validate:"required"`
+	DropTimeout   time.Duration `validate:"required"`
+	Confirmations uint64        `validate:"required,min=1"`
+}
+
+var LedgerSettings = &Ledger{}
+
+type Idempotency struct {
+	DataDir      string        `validate:"required"`
+	KeyTtl       time.Duration `validate:"required"`
+	Lease        time.Duration `validate:"required"`
+	MaxBodyBytes int64         `validate:"required,min=1"`
+}
+
+var IdempotencySettings = &Idempotency{}
+
 func Setup() {
 	_ = godotenv.Load()
 	validate := validator.New()
@@ -49,36 +103,225 @@ func Setup() {
 	}
 
 	EthereumClientSettings.Url = os.Getenv("ETHEREUM_URL")
+	EthereumClientSettings.WsUrl = os.Getenv("ETHEREUM_WS_URL")
 	gasLimitStr := os.Getenv("ETHEREUM_GAS_LIMIT")
 	gasLimit, err := strconv.Atoi(gasLimitStr)
 	if err != nil {
-		log.Fatal("ETHEREUM_GAS_LIMIT setting is not proper err: %v", err)
+		log.Fatalf("ETHEREUM_GAS_LIMIT setting is not proper err: %v", err)
 	}
 	EthereumClientSettings.GasLimit = uint64(gasLimit)
+	chainIDStr := os.Getenv("ETHEREUM_CHAIN_ID")
+	if chainIDStr != "" {
+		chainID, err := strconv.ParseUint(chainIDStr, 10, 64)
+		if err != nil {
+			log.Fatalf("ETHEREUM_CHAIN_ID setting is not proper err: %v", err)
+		}
+		EthereumClientSettings.ChainID = chainID
+	}
+	EthereumClientSettings.NonceResyncInterval = 30 * time.Second
+	nonceResyncIntervalStr := os.Getenv("ETHEREUM_NONCE_RESYNC_INTERVAL")
+	if nonceResyncIntervalStr != "" {
+		nonceResyncInterval, err := strconv.Atoi(nonceResyncIntervalStr)
+		if err != nil {
+			log.Fatalf("ETHEREUM_NONCE_RESYNC_INTERVAL setting is not proper err: %v", err)
+		}
+		EthereumClientSettings.NonceResyncInterval = time.Duration(nonceResyncInterval) * time.Second
+	}
+	EthereumClientSettings.GasMultiplier = 1.2
+	gasMultiplierStr := os.Getenv("ETHEREUM_GAS_MULTIPLIER")
+	if gasMultiplierStr != "" {
+		EthereumClientSettings.GasMultiplier, err = strconv.ParseFloat(gasMultiplierStr, 64)
+		if err != nil {
+			log.Fatalf("ETHEREUM_GAS_MULTIPLIER setting is not proper err: %v", err)
+		}
+	}
+	EthereumClientSettings.MaxBatchSize = 100
+	maxBatchSizeStr := os.Getenv("ETHEREUM_MAX_BATCH_SIZE")
+	if maxBatchSizeStr != "" {
+		EthereumClientSettings.MaxBatchSize, err = strconv.Atoi(maxBatchSizeStr)
+		if err != nil {
+			log.Fatalf("ETHEREUM_MAX_BATCH_SIZE setting is not proper err: %v", err)
+		}
+	}
+	EthereumClientSettings.LogChunkSize = 2000
+	logChunkSizeStr := os.Getenv("ETHEREUM_LOG_CHUNK_SIZE")
+	if logChunkSizeStr != "" {
+		EthereumClientSettings.LogChunkSize, err = strconv.ParseUint(logChunkSizeStr, 10, 64)
+		if err != nil {
+			log.Fatalf("ETHEREUM_LOG_CHUNK_SIZE setting is not proper err: %v", err)
+		}
+	}
+	EthereumClientSettings.LogMaxScanBlocks = 100000
+	logMaxScanBlocksStr := os.Getenv("ETHEREUM_LOG_MAX_SCAN_BLOCKS")
+	if logMaxScanBlocksStr != "" {
+		EthereumClientSettings.LogMaxScanBlocks, err = strconv.ParseUint(logMaxScanBlocksStr, 10, 64)
+		if err != nil {
+			log.Fatalf("ETHEREUM_LOG_MAX_SCAN_BLOCKS setting is not proper err: %v", err)
+		}
+	}
 	err = validate.Struct(EthereumClientSettings)
 	if err != nil {
-		log.Fatal("EthereumClient settings missing err: %v", err)
+		log.Fatalf("EthereumClient settings missing err: %v", err)
+	}
+
+	WalletSettings.Signer = os.Getenv("WALLET_SIGNER")
+	if WalletSettings.Signer == "" {
+		WalletSettings.Signer = "keystore"
+	}
+	WalletSettings.ClefUrl = os.Getenv("WALLET_CLEF_URL")
+	WalletSettings.KeystoreDir = os.Getenv("WALLET_KEYSTORE_DIR")
+	WalletSettings.Passphrase = os.Getenv("WALLET_KEYSTORE_PASSPHRASE")
+	for _, key := range strings.Split(os.Getenv("WALLET_RAW_PRIVATE_KEYS"), ",") {
+		if key = strings.TrimSpace(key); key != "" {
+			WalletSettings.RawPrivateKeys = append(WalletSettings.RawPrivateKeys, key)
+		}
+	}
+	allowRawPrivateKeyStr := os.Getenv("WALLET_ALLOW_RAW_PRIVATE_KEY")
+	if allowRawPrivateKeyStr != "" {
+		WalletSettings.AllowRawPrivateKey, err = strconv.ParseBool(allowRawPrivateKeyStr)
+		if err != nil {
+			log.Fatalf("WALLET_ALLOW_RAW_PRIVATE_KEY setting is not proper err: %v", err)
+		}
+	}
+	err = validate.Struct(WalletSettings)
+	if err != nil {
+		log.Fatalf("Wallet settings missing err: %v", err)
+	}
+
+	ContractsSettings.RegistryDir = os.Getenv("CONTRACTS_REGISTRY_DIR")
+	if ContractsSettings.RegistryDir == "" {
+		ContractsSettings.RegistryDir = "./contracts"
+	}
+	err = validate.Struct(ContractsSettings)
+	if err != nil {
+		log.Fatalf("Contracts settings missing err: %v", err)
+	}
+
+	WatchSettings.DataDir = os.Getenv("WATCH_DATA_DIR")
+	if WatchSettings.DataDir == "" {
+		WatchSettings.DataDir = "./watch"
+	}
+	WatchSettings.WebhookUrl = os.Getenv("WATCH_WEBHOOK_URL")
+	WatchSettings.WebhookSecret = os.Getenv("WATCH_WEBHOOK_SECRET")
+	WatchSettings.Confirmations = 12
+	confirmationsStr := os.Getenv("WATCH_CONFIRMATIONS")
+	if confirmationsStr != "" {
+		WatchSettings.Confirmations, err = strconv.ParseUint(confirmationsStr, 10, 64)
+		if err != nil {
+			log.Fatalf("WATCH_CONFIRMATIONS setting is not proper err: %v", err)
+		}
+	}
+	WatchSettings.PollInterval = 5 * time.Second
+	pollIntervalStr := os.Getenv("WATCH_POLL_INTERVAL")
+	if pollIntervalStr != "" {
+		pollInterval, err := strconv.Atoi(pollIntervalStr)
+		if err != nil {
+			log.Fatalf("WATCH_POLL_INTERVAL setting is not proper err: %v", err)
+		}
+		WatchSettings.PollInterval = time.Duration(pollInterval) * time.Second
+	}
+	WatchSettings.MaxRetries = 8
+	maxRetriesStr := os.Getenv("WATCH_WEBHOOK_MAX_RETRIES")
+	if maxRetriesStr != "" {
+		WatchSettings.MaxRetries, err = strconv.Atoi(maxRetriesStr)
+		if err != nil {
+			log.Fatalf("WATCH_WEBHOOK_MAX_RETRIES setting is not proper err: %v", err)
+		}
+	}
+	err = validate.Struct(WatchSettings)
+	if err != nil {
+		log.Fatalf("Watch settings missing err: %v", err)
+	}
+
+	LedgerSettings.DataDir = os.Getenv("LEDGER_DATA_DIR")
+	if LedgerSettings.DataDir == "" {
+		LedgerSettings.DataDir = "./ledger"
+	}
+	LedgerSettings.TrackInterval = 15 * time.Second
+	trackIntervalStr := os.Getenv("LEDGER_TRACK_INTERVAL")
+	if trackIntervalStr != "" {
+		trackInterval, err := strconv.Atoi(trackIntervalStr)
+		if err != nil {
+			log.Fatalf("LEDGER_TRACK_INTERVAL setting is not proper err: %v", err)
+		}
+		LedgerSettings.TrackInterval = time.Duration(trackInterval) * time.Second
+	}
+	LedgerSettings.DropTimeout = 30 * time.Minute
+	dropTimeoutStr := os.Getenv("LEDGER_DROP_TIMEOUT")
+	if dropTimeoutStr != "" {
+		dropTimeout, err := strconv.Atoi(dropTimeoutStr)
+		if err != nil {
+			log.Fatalf("LEDGER_DROP_TIMEOUT setting is not proper err: %v", err)
+		}
+		LedgerSettings.DropTimeout = time.Duration(dropTimeout) * time.Second
+	}
+	LedgerSettings.Confirmations = 12
+	ledgerConfirmationsStr := os.Getenv("LEDGER_CONFIRMATIONS")
+	if ledgerConfirmationsStr != "" {
+		LedgerSettings.Confirmations, err = strconv.ParseUint(ledgerConfirmationsStr, 10, 64)
+		if err != nil {
+			log.Fatalf("LEDGER_CONFIRMATIONS setting is not proper err: %v", err)
+		}
+	}
+	err = validate.Struct(LedgerSettings)
+	if err != nil {
+		log.Fatalf("Ledger settings missing err: %v", err)
+	}
+
+	IdempotencySettings.DataDir = os.Getenv("IDEMPOTENCY_DATA_DIR")
+	if IdempotencySettings.DataDir == "" {
+		IdempotencySettings.DataDir = "./idempotency"
+	}
+	IdempotencySettings.KeyTtl = 24 * time.Hour
+	keyTtlStr := os.Getenv("IDEMPOTENCY_KEY_TTL")
+	if keyTtlStr != "" {
+		keyTtl, err := strconv.Atoi(keyTtlStr)
+		if err != nil {
+			log.Fatalf("IDEMPOTENCY_KEY_TTL setting is not proper err: %v", err)
+		}
+		IdempotencySettings.KeyTtl = time.Duration(keyTtl) * time.Second
+	}
+	IdempotencySettings.Lease = 2 * time.Minute
+	leaseStr := os.Getenv("IDEMPOTENCY_LEASE")
+	if leaseStr != "" {
+		lease, err := strconv.Atoi(leaseStr)
+		if err != nil {
+			log.Fatalf("IDEMPOTENCY_LEASE setting is not proper err: %v", err)
+		}
+		IdempotencySettings.Lease = time.Duration(lease) * time.Second
+	}
+	IdempotencySettings.MaxBodyBytes = 1 << 20
+	maxBodyBytesStr := os.Getenv("IDEMPOTENCY_MAX_BODY_BYTES")
+	if maxBodyBytesStr != "" {
+		IdempotencySettings.MaxBodyBytes, err = strconv.ParseInt(maxBodyBytesStr, 10, 64)
+		if err != nil {
+			log.Fatalf("IDEMPOTENCY_MAX_BODY_BYTES setting is not proper err: %v", err)
+		}
+	}
+	err = validate.Struct(IdempotencySettings)
+	if err != nil {
+		log.Fatalf("Idempotency settings missing err: %v", err)
 	}
 
 	ServerSettings.HttpPort, _ = strconv.Atoi(os.Getenv("HTTP_PORT"))
 	readTimeoutStr := os.Getenv("READ_TIMEOUT")
 	ReadTimeout, err := strconv.Atoi(readTimeoutStr)
 	if err != nil {
-		log.Fatal("READ_TIMEOUT setting is not proper err: %v", err)
+		log.Fatalf("READ_TIMEOUT setting is not proper err: %v", err)
 	}
-	
+
 	ServerSettings.ReadTimeout = time.Duration(ReadTimeout * 1000000000)
 	writeTimeoutStr := os.Getenv("WRITE_TIMEOUT")
 	WriteTimeout, err := strconv.Atoi(writeTimeoutStr)
 	if err != nil {
-		log.Fatal("WRITE_TIMEOUT setting is not proper err: %v", err)
+		log.Fatalf("WRITE_TIMEOUT setting is not proper err: %v", err)
 	}
-	
+
 	ServerSettings.WriteTimeout = time.Duration(WriteTimeout * 1000000000)
 	ServerSettings.HttpPort, _ = strconv.Atoi(os.Getenv("HTTP_PORT"))
 	ServerSettings.RunMode = os.Getenv("RUN_MODE")
 	err = validate.Struct(ServerSettings)
 	if err != nil {
-		log.Fatal("Server settings missing err: %v", err)
+		log.Fatalf("Server settings missing err: %v", err)
 	}
 }
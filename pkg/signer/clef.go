@@ -0,0 +1,140 @@
+package signer
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"math/big"
+)
+
+type clefProvider struct {
+	client *rpc.Client
+}
+
+// NewClefProvider uses an external signer speaking Clef's account_* JSON-RPC API.
+func NewClefProvider(client *rpc.Client) Provider {
+	return &clefProvider{client: client}
+}
+
+func (p *clefProvider) Signer(ctx context.Context, address common.Address) (Signer, error) {
+	var addresses []common.Address
+	if err := p.client.CallContext(ctx, &addresses, "account_list"); err != nil {
+		return nil, err
+	}
+	for _, a := range addresses {
+		if a == address {
+			return &clefSigner{client: p.client, address: address}, nil
+		}
+	}
+	return nil, ErrUnknownAccount
+}
+
+type clefSigner struct {
+	client  *rpc.Client
+	address common.Address
+}
+
+type signTransactionResult struct {
+	Raw hexutil.Bytes      `json:"raw"`
+	Tx  *types.Transaction `json:"tx"`
+}
+
+func (s *clefSigner) Address() common.Address {
+	return s.address
+}
+
+func (s *clefSigner) SignTx(ctx context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	data := hexutil.Bytes(tx.Data())
+	var to *common.MixedcaseAddress
+	if tx.To() != nil {
+		t := common.NewMixedcaseAddress(*tx.To())
+		to = &t
+	}
+	args := &apitypes.SendTxArgs{
+		Data:    &data,
+		Nonce:   hexutil.Uint64(tx.Nonce()),
+		Value:   hexutil.Big(*tx.Value()),
+		Gas:     hexutil.Uint64(tx.Gas()),
+		To:      to,
+		From:    common.NewMixedcaseAddress(s.address),
+		ChainID: (*hexutil.Big)(chainID),
+	}
+	switch tx.Type() {
+	case types.LegacyTxType, types.AccessListTxType:
+		args.GasPrice = (*hexutil.Big)(tx.GasPrice())
+	case types.DynamicFeeTxType:
+		args.MaxFeePerGas = (*hexutil.Big)(tx.GasFeeCap())
+		args.MaxPriorityFeePerGas = (*hexutil.Big)(tx.GasTipCap())
+	default:
+		return nil, fmt.Errorf("unsupported transaction type %d", tx.Type())
+	}
+	if tx.Type() != types.LegacyTxType {
+		accessList := tx.AccessList()
+		args.AccessList = &accessList
+	}
+
+	var result signTransactionResult
+	if err := s.client.CallContext(ctx, &result, "account_signTransaction", args); err != nil {
+		return nil, err
+	}
+	if err := s.checkSigned(tx, result.Tx, chainID); err != nil {
+		return nil, err
+	}
+	return result.Tx, nil
+}
+
+// checkSigned makes sure Clef signed the transaction that was asked for. Clef rules and
+// operators may modify a transaction before signing it, which must not go out unnoticed.
+func (s *clefSigner) checkSigned(requested *types.Transaction, signed *types.Transaction, chainID *big.Int) error {
+	if signed == nil {
+		return errors.New("external signer returned no transaction")
+	}
+	if !signed.Protected() || signed.ChainId().Cmp(chainID) != 0 {
+		return fmt.Errorf("external signer did not sign for chain %s", chainID)
+	}
+	from, err := types.Sender(types.LatestSignerForChainID(chainID), signed)
+	if err != nil {
+		return fmt.Errorf("external signer returned an invalid signature: %w", err)
+	}
+	if from != s.address {
+		return fmt.Errorf("external signer signed for %s instead of %s", from.Hex(), s.address.Hex())
+	}
+	sameTo := requested.To() == nil && signed.To() == nil ||
+		requested.To() != nil && signed.To() != nil && *requested.To() == *signed.To()
+	if signed.Type() != requested.Type() ||
+		signed.Nonce() != requested.Nonce() ||
+		!sameTo ||
+		signed.Value().Cmp(requested.Value()) != 0 ||
+		!bytes.Equal(signed.Data(), requested.Data()) ||
+		signed.Gas() != requested.Gas() ||
+		signed.GasFeeCap().Cmp(requested.GasFeeCap()) != 0 ||
+		signed.GasTipCap().Cmp(requested.GasTipCap()) != 0 {
+		return errors.New("external signer modified the transaction")
+	}
+	return nil
+}
+
+func (s *clefSigner) SignMessage(ctx context.Context, message []byte) ([]byte, error) {
+	var signature hexutil.Bytes
+	address := common.NewMixedcaseAddress(s.address)
+	err := s.client.CallContext(ctx, &signature, "account_signData", accounts.MimetypeTextPlain, &address, hexutil.Encode(message))
+	if err != nil {
+		return nil, err
+	}
+	if len(signature) != crypto.SignatureLength {
+		return nil, fmt.Errorf("unexpected signature length %d", len(signature))
+	}
+	// Clef returns the legacy 27/28 recovery id, the other signers return 0/1.
+	if signature[64] == 27 || signature[64] == 28 {
+		signature[64] -= 27
+	}
+	return signature, nil
+}
@@ -0,0 +1,163 @@
+package signer_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"golang-ethereum-example-api/pkg/signer"
+	"golang-ethereum-example-api/pkg/signer/signertest"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+var chainID = big.NewInt(1337)
+
+func newClefSigner(t *testing.T, configure func(clef *signertest.FakeClef)) (signer.Signer, *ecdsa.PrivateKey) {
+	t.Helper()
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	clef := signertest.NewFakeClef(key)
+	if configure != nil {
+		configure(clef)
+	}
+	client, err := clef.Client()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(client.Close)
+	s, err := signer.NewClefProvider(client).Signer(context.Background(), crypto.PubkeyToAddress(key.PublicKey))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s, key
+}
+
+func dynamicFeeTx() *types.Transaction {
+	to := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	return types.NewTx(&types.DynamicFeeTx{
+		ChainID:   chainID,
+		Nonce:     3,
+		GasTipCap: big.NewInt(1_000_000_000),
+		GasFeeCap: big.NewInt(30_000_000_000),
+		Gas:       21000,
+		To:        &to,
+		Value:     big.NewInt(1_000),
+	})
+}
+
+func legacyTx() *types.Transaction {
+	to := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	return types.NewTx(&types.LegacyTx{
+		Nonce:    3,
+		GasPrice: big.NewInt(30_000_000_000),
+		Gas:      21000,
+		To:       &to,
+		Value:    big.NewInt(1_000),
+	})
+}
+
+func TestClefSignTx(t *testing.T) {
+	for name, tx := range map[string]*types.Transaction{"legacy": legacyTx(), "dynamic fee": dynamicFeeTx()} {
+		t.Run(name, func(t *testing.T) {
+			s, key := newClefSigner(t, nil)
+			signed, err := s.SignTx(context.Background(), tx, chainID)
+			if err != nil {
+				t.Fatalf("SignTx: %v", err)
+			}
+			from, err := types.Sender(types.LatestSignerForChainID(chainID), signed)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if from != crypto.PubkeyToAddress(key.PublicKey) {
+				t.Fatalf("signed by %s", from.Hex())
+			}
+			if signed.Hash() == tx.Hash() || signed.Nonce() != tx.Nonce() || signed.Value().Cmp(tx.Value()) != 0 {
+				t.Fatal("signed transaction does not match the request")
+			}
+		})
+	}
+}
+
+func TestClefSignTxRejectsModifiedTransaction(t *testing.T) {
+	s, _ := newClefSigner(t, func(clef *signertest.FakeClef) {
+		clef.Tamper = func(tx *types.Transaction) *types.Transaction {
+			to := common.HexToAddress("0x00000000000000000000000000000000000000bb")
+			return types.NewTx(&types.DynamicFeeTx{
+				ChainID:   tx.ChainId(),
+				Nonce:     tx.Nonce(),
+				GasTipCap: tx.GasTipCap(),
+				GasFeeCap: tx.GasFeeCap(),
+				Gas:       tx.Gas(),
+				To:        &to,
+				Value:     tx.Value(),
+			})
+		}
+	})
+	_, err := s.SignTx(context.Background(), dynamicFeeTx(), chainID)
+	if err == nil || !strings.Contains(err.Error(), "modified the transaction") {
+		t.Fatalf("expected a modified transaction error, got %v", err)
+	}
+}
+
+func TestClefSignTxRejectsUnprotectedSignature(t *testing.T) {
+	s, _ := newClefSigner(t, func(clef *signertest.FakeClef) {
+		clef.SignerFor = func(*big.Int) types.Signer { return types.HomesteadSigner{} }
+	})
+	_, err := s.SignTx(context.Background(), legacyTx(), chainID)
+	if err == nil || !strings.Contains(err.Error(), "did not sign for chain") {
+		t.Fatalf("expected a replay protection error, got %v", err)
+	}
+}
+
+func TestClefSignTxRejectsWrongChain(t *testing.T) {
+	s, _ := newClefSigner(t, func(clef *signertest.FakeClef) {
+		clef.SignerFor = func(id *big.Int) types.Signer {
+			return types.LatestSignerForChainID(new(big.Int).Add(id, big.NewInt(1)))
+		}
+	})
+	_, err := s.SignTx(context.Background(), legacyTx(), chainID)
+	if err == nil || !strings.Contains(err.Error(), "did not sign for chain") {
+		t.Fatalf("expected a wrong chain error, got %v", err)
+	}
+}
+
+func TestClefProviderUnknownAccount(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	client, err := signertest.NewFakeClef(key).Client()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	_, err = signer.NewClefProvider(client).Signer(context.Background(), common.HexToAddress("0x01"))
+	if err != signer.ErrUnknownAccount {
+		t.Fatalf("expected ErrUnknownAccount, got %v", err)
+	}
+}
+
+func TestClefSignMessage(t *testing.T) {
+	s, key := newClefSigner(t, nil)
+	message := []byte("hello")
+	signature, err := s.SignMessage(context.Background(), message)
+	if err != nil {
+		t.Fatalf("SignMessage: %v", err)
+	}
+	if signature[64] > 1 {
+		t.Fatalf("expected a 0/1 recovery id, got %d", signature[64])
+	}
+	publicKey, err := crypto.SigToPub(accounts.TextHash(message), signature)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if crypto.PubkeyToAddress(*publicKey) != crypto.PubkeyToAddress(key.PublicKey) {
+		t.Fatal("signature does not recover to the account")
+	}
+}
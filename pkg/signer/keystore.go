@@ -0,0 +1,80 @@
+package signer
+
+import (
+	"context"
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"math/big"
+	"sync"
+)
+
+type keystoreProvider struct {
+	keyStore   *keystore.KeyStore
+	passphrase string
+
+	mu       sync.Mutex
+	unlocked map[common.Address]bool
+}
+
+// NewKeystoreProvider unlocks every account of the keystore once. Decrypting a key file is
+// deliberately slow and memory hungry, so keys are kept unlocked in memory instead of being
+// decrypted for every signature. Accounts created later are unlocked on first use.
+func NewKeystoreProvider(keyStore *keystore.KeyStore, passphrase string) (Provider, error) {
+	p := &keystoreProvider{
+		keyStore:   keyStore,
+		passphrase: passphrase,
+		unlocked:   make(map[common.Address]bool),
+	}
+	for _, account := range keyStore.Accounts() {
+		err := p.unlock(account)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return p, nil
+}
+
+func (p *keystoreProvider) Signer(_ context.Context, address common.Address) (Signer, error) {
+	account, err := p.keyStore.Find(accounts.Account{Address: address})
+	if err != nil {
+		return nil, ErrUnknownAccount
+	}
+	err = p.unlock(account)
+	if err != nil {
+		return nil, err
+	}
+	return &keystoreSigner{keyStore: p.keyStore, account: account}, nil
+}
+
+func (p *keystoreProvider) unlock(account accounts.Account) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.unlocked[account.Address] {
+		return nil
+	}
+	err := p.keyStore.Unlock(account, p.passphrase)
+	if err != nil {
+		return err
+	}
+	p.unlocked[account.Address] = true
+	return nil
+}
+
+type keystoreSigner struct {
+	keyStore *keystore.KeyStore
+	account  accounts.Account
+}
+
+func (s *keystoreSigner) Address() common.Address {
+	return s.account.Address
+}
+
+func (s *keystoreSigner) SignTx(_ context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return s.keyStore.SignTx(s.account, tx, chainID)
+}
+
+func (s *keystoreSigner) SignMessage(_ context.Context, message []byte) ([]byte, error) {
+	return s.keyStore.SignHash(s.account, accounts.TextHash(message))
+}
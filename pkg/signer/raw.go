@@ -0,0 +1,62 @@
+package signer
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"math/big"
+	"strings"
+)
+
+type rawProvider struct {
+	signers map[common.Address]Signer
+}
+
+// NewRawProvider signs with a fixed set of hex encoded private keys held in memory.
+func NewRawProvider(privateKeysHex []string) (Provider, error) {
+	p := &rawProvider{signers: make(map[common.Address]Signer, len(privateKeysHex))}
+	for _, privateKeyHex := range privateKeysHex {
+		s, err := NewRawSigner(privateKeyHex)
+		if err != nil {
+			return nil, err
+		}
+		p.signers[s.Address()] = s
+	}
+	return p, nil
+}
+
+func (p *rawProvider) Signer(_ context.Context, address common.Address) (Signer, error) {
+	s, ok := p.signers[address]
+	if !ok {
+		return nil, ErrUnknownAccount
+	}
+	return s, nil
+}
+
+type rawSigner struct {
+	key     *ecdsa.PrivateKey
+	address common.Address
+}
+
+func NewRawSigner(privateKeyHex string) (Signer, error) {
+	key, err := crypto.HexToECDSA(strings.TrimPrefix(privateKeyHex, "0x"))
+	if err != nil {
+		return nil, err
+	}
+	return &rawSigner{key: key, address: crypto.PubkeyToAddress(key.PublicKey)}, nil
+}
+
+func (s *rawSigner) Address() common.Address {
+	return s.address
+}
+
+func (s *rawSigner) SignTx(_ context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return types.SignTx(tx, types.LatestSignerForChainID(chainID), s.key)
+}
+
+func (s *rawSigner) SignMessage(_ context.Context, message []byte) ([]byte, error) {
+	return crypto.Sign(accounts.TextHash(message), s.key)
+}
@@ -0,0 +1,59 @@
+package signer
+
+import (
+	"context"
+	"errors"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"go.uber.org/zap"
+	"golang-ethereum-example-api/pkg/logging"
+	"golang-ethereum-example-api/pkg/settings"
+	"math/big"
+)
+
+var ErrUnknownAccount = errors.New("account is not managed by the signer")
+
+// Signer signs on behalf of a single account. Implementations may keep the key in memory,
+// in an encrypted keystore or delegate to an external custody service.
+type Signer interface {
+	Address() common.Address
+	SignTx(ctx context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error)
+	SignMessage(ctx context.Context, message []byte) ([]byte, error)
+}
+
+// Provider hands out the signer of a managed account, returning ErrUnknownAccount for any
+// address it does not control.
+type Provider interface {
+	Signer(ctx context.Context, address common.Address) (Signer, error)
+}
+
+var provider Provider
+
+func Setup(wallet *settings.Wallet, keyStore *keystore.KeyStore, logger *logging.LogWrapper) {
+	switch wallet.Signer {
+	case "clef":
+		client, err := rpc.Dial(wallet.ClefUrl)
+		if err != nil {
+			logger.Fatal("Failed to connect to the external signer", zap.Error(err))
+		}
+		provider = NewClefProvider(client)
+	case "raw":
+		var err error
+		provider, err = NewRawProvider(wallet.RawPrivateKeys)
+		if err != nil {
+			logger.Fatal("Failed to load the raw private keys", zap.Error(err))
+		}
+	default:
+		var err error
+		provider, err = NewKeystoreProvider(keyStore, wallet.Passphrase)
+		if err != nil {
+			logger.Fatal("Failed to unlock the keystore accounts", zap.Error(err))
+		}
+	}
+}
+
+func GetProvider() Provider {
+	return provider
+}
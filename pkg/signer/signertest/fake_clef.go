@@ -0,0 +1,107 @@
+// Package signertest provides test doubles for the signer package.
+package signertest
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"golang-ethereum-example-api/pkg/signer"
+	"math/big"
+	"sort"
+)
+
+// FakeClef serves the subset of Clef's account_* API used by the Clef signer from in-memory
+// keys, approving every request. Tamper, when set, may change a transaction before it is
+// signed, like a Clef rule could. SignerFor, when set, replaces the signer of a chain id.
+type FakeClef struct {
+	keys      map[common.Address]*ecdsa.PrivateKey
+	Tamper    func(tx *types.Transaction) *types.Transaction
+	SignerFor func(chainID *big.Int) types.Signer
+}
+
+type signTransactionResult struct {
+	Raw hexutil.Bytes      `json:"raw"`
+	Tx  *types.Transaction `json:"tx"`
+}
+
+func NewFakeClef(keys ...*ecdsa.PrivateKey) *FakeClef {
+	f := &FakeClef{keys: make(map[common.Address]*ecdsa.PrivateKey)}
+	for _, key := range keys {
+		f.keys[crypto.PubkeyToAddress(key.PublicKey)] = key
+	}
+	return f
+}
+
+// Client returns an in-process JSON-RPC client for signer.NewClefProvider.
+func (f *FakeClef) Client() (*rpc.Client, error) {
+	server := rpc.NewServer()
+	err := server.RegisterName("account", &fakeClefApi{clef: f})
+	if err != nil {
+		return nil, err
+	}
+	return rpc.DialInProc(server), nil
+}
+
+type fakeClefApi struct {
+	clef *FakeClef
+}
+
+func (a *fakeClefApi) List(_ context.Context) ([]common.Address, error) {
+	addresses := make([]common.Address, 0, len(a.clef.keys))
+	for address := range a.clef.keys {
+		addresses = append(addresses, address)
+	}
+	sort.Slice(addresses, func(i, j int) bool { return addresses[i].Cmp(addresses[j]) < 0 })
+	return addresses, nil
+}
+
+func (a *fakeClefApi) SignTransaction(_ context.Context, args apitypes.SendTxArgs, _ *string) (*signTransactionResult, error) {
+	key, ok := a.clef.keys[args.From.Address()]
+	if !ok {
+		return nil, signer.ErrUnknownAccount
+	}
+	if args.ChainID == nil {
+		return nil, errors.New("chain id is required")
+	}
+	tx := args.ToTransaction()
+	if a.clef.Tamper != nil {
+		tx = a.clef.Tamper(tx)
+	}
+	txSigner := types.LatestSignerForChainID(args.ChainID.ToInt())
+	if a.clef.SignerFor != nil {
+		txSigner = a.clef.SignerFor(args.ChainID.ToInt())
+	}
+	signed, err := types.SignTx(tx, txSigner, key)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := signed.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return &signTransactionResult{Raw: raw, Tx: signed}, nil
+}
+
+func (a *fakeClefApi) SignData(_ context.Context, contentType string, addr common.MixedcaseAddress, data hexutil.Bytes) (hexutil.Bytes, error) {
+	if contentType != accounts.MimetypeTextPlain {
+		return nil, errors.New("unsupported content type")
+	}
+	key, ok := a.clef.keys[addr.Address()]
+	if !ok {
+		return nil, signer.ErrUnknownAccount
+	}
+	signature, err := crypto.Sign(accounts.TextHash(data), key)
+	if err != nil {
+		return nil, err
+	}
+	// Clef answers with the legacy 27/28 recovery id.
+	signature[64] += 27
+	return signature, nil
+}
@@ -0,0 +1,57 @@
+package util
+
+import (
+	"errors"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rpc"
+	"math/big"
+	"regexp"
+	"strings"
+)
+
+var blockHashRegex = regexp.MustCompile("^0x[0-9a-fA-F]{64}$")
+
+var blockTags = map[string]rpc.BlockNumber{
+	"earliest":  rpc.EarliestBlockNumber,
+	"latest":    rpc.LatestBlockNumber,
+	"pending":   rpc.PendingBlockNumber,
+	"safe":      rpc.SafeBlockNumber,
+	"finalized": rpc.FinalizedBlockNumber,
+}
+
+// BlockReference points at a block either by number or by hash. Tags are kept as the negative
+// numbers used by go-ethereum, so Number can be passed to ethclient as is.
+type BlockReference struct {
+	Number *big.Int
+	Hash   *common.Hash
+}
+
+func (r *BlockReference) IsPending() bool {
+	return r.Hash == nil && r.Number.Cmp(big.NewInt(int64(rpc.PendingBlockNumber))) == 0
+}
+
+// ParseBlockReference accepts a decimal or hex block number, a block hash or one of the
+// latest, pending, safe, finalized and earliest tags. An empty string means latest.
+func ParseBlockReference(block string) (*BlockReference, error) {
+	if block == "" {
+		block = "latest"
+	}
+	if tag, ok := blockTags[strings.ToLower(block)]; ok {
+		return &BlockReference{Number: big.NewInt(int64(tag))}, nil
+	}
+	if blockHashRegex.MatchString(block) {
+		hash := common.HexToHash(block)
+		return &BlockReference{Hash: &hash}, nil
+	}
+	base := 10
+	digits := block
+	if strings.HasPrefix(block, "0x") {
+		base = 16
+		digits = block[2:]
+	}
+	number, ok := new(big.Int).SetString(digits, base)
+	if !ok || number.Sign() < 0 || !number.IsUint64() {
+		return nil, errors.New("block must be a number, a hash or one of latest, pending, safe, finalized and earliest")
+	}
+	return &BlockReference{Number: number}, nil
+}
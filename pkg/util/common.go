@@ -3,12 +3,52 @@ package util
 type ErrorInfo struct {
 	HttpCode int
 	Message  string
+	Detail   string
 	Err      error
 }
 
 const (
-	ValidationErrorMessage            = "validation error"
-	BindingErrorMessage               = "binding error"
-	InternalServiceErrorMessage       = "internal Service Error"
-	InvalidAccountAddressErrorMessage = "invalid Account Address"
+	ValidationErrorMessage                 = "validation error"
+	BindingErrorMessage                    = "binding error"
+	InternalServiceErrorMessage            = "internal Service Error"
+	InvalidAccountAddressErrorMessage      = "invalid Account Address"
+	InvalidFeeErrorMessage                 = "invalid Fee Parameters"
+	InvalidAmountErrorMessage              = "invalid Amount"
+	RawPrivateKeyDisabledErrorMessage      = "raw Private Key Disabled"
+	InvalidPrivateKeyErrorMessage          = "invalid Private Key"
+	InvalidTransactionHashErrorMessage     = "invalid Transaction Hash"
+	TransactionNotFoundErrorMessage        = "transaction Not Found"
+	InvalidGasLimitErrorMessage            = "invalid Gas Limit"
+	GasEstimationErrorMessage              = "gas Estimation Failed"
+	InvalidBlockErrorMessage               = "invalid Block"
+	BlockNotFoundErrorMessage              = "block Not Found"
+	BatchTooLargeErrorMessage              = "batch Too Large"
+	InvalidTokenErrorMessage               = "invalid Token"
+	ExecutionRevertedErrorMessage          = "execution Reverted"
+	InsufficientBalanceErrorMessage        = "insufficient Balance"
+	InvalidTokenIdErrorMessage             = "invalid Token Id"
+	UnknownAccountErrorMessage             = "unknown Account"
+	InvalidAbiErrorMessage                 = "invalid ABI"
+	ContractNotFoundErrorMessage           = "contract Not Found"
+	MethodNotFoundErrorMessage             = "method Not Found"
+	InvalidArgumentsErrorMessage           = "invalid Arguments"
+	MethodNotPayableErrorMessage           = "method Not Payable"
+	ReadOnlyMethodErrorMessage             = "read Only Method"
+	InvalidTopicErrorMessage               = "invalid Topic"
+	InvalidCursorErrorMessage              = "invalid Cursor"
+	StreamingDisabledErrorMessage          = "streaming Disabled"
+	AddressNotWatchedErrorMessage          = "address Not Watched"
+	MissingWebhookUrlErrorMessage          = "missing Webhook Url"
+	InvalidIdempotencyKeyErrorMessage      = "invalid Idempotency Key"
+	IdempotencyKeyReusedErrorMessage       = "idempotency Key Reused"
+	RequestInProgressErrorMessage          = "request In Progress"
+	RequestTooLargeErrorMessage            = "request Too Large"
+	TransactionAlreadyMinedErrorMessage    = "transaction Already Mined"
+	InvalidRawTransactionErrorMessage      = "invalid Raw Transaction"
+	InvalidChainIdErrorMessage             = "invalid Chain Id"
+	InvalidSignatureErrorMessage           = "invalid Signature"
+	InvalidNonceErrorMessage               = "invalid Nonce"
+	AccountCreationUnsupportedErrorMessage = "account Creation Unsupported"
+	ApproveNotSentErrorMessage             = "approve Not Sent"
+	UnsupportedTransactionTypeErrorMessage = "unsupported Transaction Type"
 )
@@ -0,0 +1,81 @@
+package util
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+const (
+	UnitEther = "ether"
+	UnitGwei  = "gwei"
+	UnitWei   = "wei"
+
+	EtherDecimals = 18
+)
+
+var unitDecimals = map[string]int{
+	UnitEther: EtherDecimals,
+	UnitGwei:  9,
+	UnitWei:   0,
+}
+
+// UnitDecimals returns the number of decimals of the given unit. An empty unit means ether.
+func UnitDecimals(unit string) (int, bool) {
+	if unit == "" {
+		unit = UnitEther
+	}
+	decimals, ok := unitDecimals[unit]
+	return decimals, ok
+}
+
+// ParseAmount converts a decimal amount expressed in ether, gwei or wei into wei.
+func ParseAmount(amount string, unit string) (*big.Int, error) {
+	decimals, ok := UnitDecimals(unit)
+	if !ok {
+		return nil, fmt.Errorf("unknown unit %q", unit)
+	}
+	return ParseUnits(amount, decimals)
+}
+
+// ParseUnits converts a non-negative decimal string into its integer representation with the
+// given number of decimals, e.g. ParseUnits("0.1", 18) is 100000000000000000.
+func ParseUnits(amount string, decimals int) (*big.Int, error) {
+	whole, fraction, _ := strings.Cut(amount, ".")
+	if whole == "" || strings.ContainsAny(fraction, ".") {
+		return nil, errors.New("amount is not a decimal number")
+	}
+	if len(fraction) > decimals {
+		return nil, fmt.Errorf("amount has more than %d fractional digits", decimals)
+	}
+	digits := whole + fraction + strings.Repeat("0", decimals-len(fraction))
+	for _, c := range digits {
+		if c < '0' || c > '9' {
+			return nil, errors.New("amount is not a decimal number")
+		}
+	}
+	value, ok := new(big.Int).SetString(digits, 10)
+	if !ok {
+		return nil, errors.New("amount is not a decimal number")
+	}
+	return value, nil
+}
+
+// FormatUnits renders an integer amount with the given number of decimals, trimming trailing zeros.
+func FormatUnits(value *big.Int, decimals int) string {
+	digits := new(big.Int).Abs(value).String()
+	if len(digits) <= decimals {
+		digits = strings.Repeat("0", decimals-len(digits)+1) + digits
+	}
+	whole := digits[:len(digits)-decimals]
+	fraction := strings.TrimRight(digits[len(digits)-decimals:], "0")
+	sign := ""
+	if value.Sign() < 0 {
+		sign = "-"
+	}
+	if fraction == "" {
+		return sign + whole
+	}
+	return sign + whole + "." + fraction
+}
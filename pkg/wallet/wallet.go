@@ -0,0 +1,21 @@
+package wallet
+
+import (
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"golang-ethereum-example-api/pkg/settings"
+)
+
+var keyStore *keystore.KeyStore
+
+// Setup opens the keystore directory. With an external signer there is no local keystore
+// and GetKeyStore returns nil.
+func Setup(wallet *settings.Wallet) {
+	if wallet.Signer != "keystore" {
+		return
+	}
+	keyStore = keystore.NewKeyStore(wallet.KeystoreDir, keystore.StandardScryptN, keystore.StandardScryptP)
+}
+
+func GetKeyStore() *keystore.KeyStore {
+	return keyStore
+}
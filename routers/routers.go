@@ -2,26 +2,81 @@ package routers
 
 import (
 	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
 	"golang-ethereum-example-api/controller"
+	"golang-ethereum-example-api/middleware"
 	ethereumClient "golang-ethereum-example-api/pkg/geth_client"
 	"golang-ethereum-example-api/pkg/logging"
 	"golang-ethereum-example-api/pkg/settings"
+	"golang-ethereum-example-api/pkg/signer"
+	"golang-ethereum-example-api/pkg/wallet"
 	"golang-ethereum-example-api/services"
 )
 
-func BuildRouter() *gin.Engine {
+// BuildRouter returns the router together with the background workers that have to run
+// alongside the HTTP server.
+func BuildRouter() (*gin.Engine, []services.Worker) {
 	router := newRouter()
 	logger := logging.GetLogger()
 
-	accountService := services.NewAccountService(ethereumClient.GetClient(), logger)
+	idempotencyStore, err := middleware.NewIdempotencyStore(settings.IdempotencySettings.DataDir, settings.IdempotencySettings.KeyTtl, settings.IdempotencySettings.Lease, logger)
+	if err != nil {
+		logger.Fatal("Opening idempotency store error", zap.Error(err))
+	}
+	router.Use(middleware.Idempotency(idempotencyStore, settings.IdempotencySettings.MaxBodyBytes, logger))
+
+	tokenMetadataCache := services.NewTokenMetadataCache(ethereumClient.GetClient(), logger)
+	accountService := services.NewAccountService(ethereumClient.GetClient(), wallet.GetKeyStore(), tokenMetadataCache, settings.WalletSettings, logger)
 	controller.NewAccountController(&controller.AccountControllerConfig{
 		R: router, Service: accountService})
 
-	transferService := services.NewTransferService(ethereumClient.GetClient(), settings.EthereumClientSettings, logger)
+	transferLedger, err := services.NewTransferLedger(settings.LedgerSettings.DataDir)
+	if err != nil {
+		logger.Fatal("Opening transfer ledger error", zap.Error(err))
+	}
+	nonceManager := services.NewNonceManager(ethereumClient.GetClient(), settings.EthereumClientSettings.NonceResyncInterval, logger)
+	transferService := services.NewTransferService(ethereumClient.GetClient(), settings.EthereumClientSettings, settings.ServerSettings, settings.WalletSettings, signer.GetProvider(), nonceManager, tokenMetadataCache, transferLedger, ethereumClient.GetChainID(), logger)
 	controller.NewTransferController(&controller.TransferControllerConfig{
 		R: router, Service: transferService,
 	})
-	return router
+
+	contractRegistry, err := services.NewContractRegistry(settings.ContractsSettings.RegistryDir)
+	if err != nil {
+		logger.Fatal("Loading contract registry error", zap.Error(err))
+	}
+	contractService := services.NewContractService(ethereumClient.GetClient(), settings.EthereumClientSettings, settings.ServerSettings, settings.WalletSettings, signer.GetProvider(), nonceManager, transferLedger, contractRegistry, ethereumClient.GetChainID(), logger)
+	controller.NewContractController(&controller.ContractControllerConfig{
+		R: router, Service: contractService,
+	})
+
+	logService := services.NewLogService(ethereumClient.GetClient(), settings.EthereumClientSettings, contractRegistry, logger)
+	controller.NewLogController(&controller.LogControllerConfig{
+		R: router, Service: logService,
+	})
+
+	var dialWebSocket services.DialFunc
+	if ethereumClient.WebSocketEnabled() {
+		dialWebSocket = ethereumClient.DialWebSocket
+	}
+	streamService := services.NewStreamService(dialWebSocket, contractRegistry, logger)
+	controller.NewStreamController(&controller.StreamControllerConfig{
+		R: router, Service: streamService,
+	})
+
+	nftService := services.NewNftService(ethereumClient.GetClient(), logger)
+	controller.NewNftController(&controller.NftControllerConfig{
+		R: router, Service: nftService,
+	})
+
+	watchService, err := services.NewWatchService(ethereumClient.GetClient(), settings.WatchSettings, tokenMetadataCache, ethereumClient.GetChainID(), logger)
+	if err != nil {
+		logger.Fatal("Loading watchlist error", zap.Error(err))
+	}
+	controller.NewWatchController(&controller.WatchControllerConfig{
+		R: router, Service: watchService,
+	})
+	transferTracker := services.NewTransferTracker(ethereumClient.GetClient(), transferLedger, settings.LedgerSettings, logger)
+	return router, []services.Worker{watchService, transferTracker, idempotencyStore}
 }
 
 func newRouter() *gin.Engine {
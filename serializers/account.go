@@ -3,18 +3,60 @@ package serializers
 import (
 	"context"
 	"errors"
+	"fmt"
+	"golang-ethereum-example-api/pkg/settings"
 	"golang-ethereum-example-api/pkg/util"
-	"math/big"
 	"net/http"
 )
 
 type GetBalanceRequest struct {
 	Address string `uri:"address" validate:"required"`
+	Block   string `form:"block"`
 }
 
 type GetBalanceResponse struct {
-	Address  string     `json:"address,omitempty"`
-	EthValue *big.Float `json:"ethValue,omitempty"`
+	Address     string  `json:"address,omitempty"`
+	Wei         string  `json:"wei,omitempty"`
+	EthValue    string  `json:"ethValue,omitempty"`
+	BlockNumber *uint64 `json:"blockNumber,omitempty"`
+	BlockHash   string  `json:"blockHash,omitempty"`
+	Error       string  `json:"error,omitempty"`
+}
+
+type GetBalancesRequest struct {
+	Addresses []string `json:"addresses" validate:"required,min=1"`
+	Block     string   `json:"block"`
+}
+
+func (r *GetBalancesRequest) Validate(ctx context.Context) *util.ErrorInfo {
+	errInfo := validate(ctx, r)
+	if errInfo != nil {
+		return errInfo
+	}
+	maxBatchSize := settings.EthereumClientSettings.MaxBatchSize
+	if len(r.Addresses) > maxBatchSize {
+		return &util.ErrorInfo{
+			HttpCode: http.StatusBadRequest,
+			Message:  util.BatchTooLargeErrorMessage,
+			Err:      fmt.Errorf("at most %d addresses can be requested at once", maxBatchSize),
+		}
+	}
+	for _, address := range r.Addresses {
+		if !addressValidationRegex.MatchString(address) {
+			return &util.ErrorInfo{
+				HttpCode: http.StatusBadRequest,
+				Message:  util.InvalidAccountAddressErrorMessage,
+				Err:      fmt.Errorf("invalid address %s", address),
+			}
+		}
+	}
+	return validateBlock(r.Block)
+}
+
+type GetBalancesResponse struct {
+	BlockNumber *uint64              `json:"blockNumber,omitempty"`
+	BlockHash   string               `json:"blockHash,omitempty"`
+	Balances    []GetBalanceResponse `json:"balances"`
 }
 
 func (r *GetBalanceRequest) Validate(ctx context.Context) *util.ErrorInfo {
@@ -30,9 +72,87 @@ func (r *GetBalanceRequest) Validate(ctx context.Context) *util.ErrorInfo {
 			Err:      errors.New("invalid address"),
 		}
 	}
+	return validateBlock(r.Block)
+}
+
+func validateBlock(block string) *util.ErrorInfo {
+	_, err := util.ParseBlockReference(block)
+	if err != nil {
+		return &util.ErrorInfo{
+			HttpCode: http.StatusBadRequest,
+			Message:  util.InvalidBlockErrorMessage,
+			Err:      err,
+		}
+	}
+	return nil
+}
+
+type GetTokenBalanceRequest struct {
+	Address string `uri:"address" validate:"required"`
+	Token   string `uri:"token" validate:"required"`
+}
+
+func (r *GetTokenBalanceRequest) Validate(ctx context.Context) *util.ErrorInfo {
+	errInfo := validate(ctx, r)
+	if errInfo != nil {
+		return errInfo
+	}
+	isValidAddress := addressValidationRegex.MatchString(r.Address)
+	isValidToken := addressValidationRegex.MatchString(r.Token)
+	if !isValidAddress || !isValidToken {
+		return &util.ErrorInfo{
+			HttpCode: http.StatusBadRequest,
+			Message:  util.InvalidAccountAddressErrorMessage,
+			Err:      errors.New("invalid address"),
+		}
+	}
 	return nil
 }
 
+type GetTokenBalanceResponse struct {
+	Address  string `json:"address,omitempty"`
+	Token    string `json:"token,omitempty"`
+	Symbol   string `json:"symbol,omitempty"`
+	Decimals uint8  `json:"decimals"`
+	Balance  string `json:"balance,omitempty"`
+	Value    string `json:"value,omitempty"`
+}
+
+type GetTokenAllowanceRequest struct {
+	Address string `uri:"address" validate:"required"`
+	Token   string `uri:"token" validate:"required"`
+	Spender string `uri:"spender" validate:"required"`
+}
+
+func (r *GetTokenAllowanceRequest) Validate(ctx context.Context) *util.ErrorInfo {
+	errInfo := validate(ctx, r)
+	if errInfo != nil {
+		return errInfo
+	}
+	isValidAddress := addressValidationRegex.MatchString(r.Address)
+	isValidToken := addressValidationRegex.MatchString(r.Token)
+	isValidSpender := addressValidationRegex.MatchString(r.Spender)
+	if !isValidAddress || !isValidToken || !isValidSpender {
+		return &util.ErrorInfo{
+			HttpCode: http.StatusBadRequest,
+			Message:  util.InvalidAccountAddressErrorMessage,
+			Err:      errors.New("invalid address"),
+		}
+	}
+	return nil
+}
+
+type GetTokenAllowanceResponse struct {
+	Address   string `json:"address,omitempty"`
+	Token     string `json:"token,omitempty"`
+	Spender   string `json:"spender,omitempty"`
+	Symbol    string `json:"symbol,omitempty"`
+	Decimals  uint8  `json:"decimals"`
+	Allowance string `json:"allowance,omitempty"`
+	Value     string `json:"value,omitempty"`
+	Unlimited bool   `json:"unlimited"`
+}
+
 type CreateAccountResponse struct {
 	Address    string `json:"accountId,omitempty"`
 	PrivateKey string `json:"privateKey,omitempty"`
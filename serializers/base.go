@@ -2,6 +2,7 @@ package serializers
 
 import (
 	"context"
+	"errors"
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
 	"golang-ethereum-example-api/pkg/util"
@@ -10,9 +11,18 @@ import (
 )
 
 var addressValidationRegex = regexp.MustCompile("^0x[0-9a-fA-F]{40}$")
+var integerValidationRegex = regexp.MustCompile("^[0-9]+$")
+var privateKeyValidationRegex = regexp.MustCompile("^0x[0-9a-fA-F]{64}$")
+var hashValidationRegex = regexp.MustCompile("^0x[0-9a-fA-F]{64}$")
+var hexDataValidationRegex = regexp.MustCompile("^0x([0-9a-fA-F]{2})*$")
+var decimalValidationRegex = regexp.MustCompile(`^[0-9]+(\.[0-9]+)?$`)
+var logCursorValidationRegex = regexp.MustCompile("^[0-9]+:[0-9]+$")
+var contractNameValidationRegex = regexp.MustCompile("^[A-Za-z0-9_-]{1,64}$")
+var signatureValueValidationRegex = regexp.MustCompile("^0x[0-9a-fA-F]{1,64}$")
 
 type ErrorResponse struct {
 	Message string
+	Detail  string `json:",omitempty"`
 }
 
 type Serializer struct {
@@ -22,9 +32,34 @@ type Serializer struct {
 func (s *Serializer) ErrorResponse(e *util.ErrorInfo) {
 	s.C.JSON(e.HttpCode, ErrorResponse{
 		Message: e.Message,
+		Detail:  e.Detail,
 	})
 }
 
+func validateAddresses(addresses ...string) *util.ErrorInfo {
+	for _, address := range addresses {
+		if !addressValidationRegex.MatchString(address) {
+			return &util.ErrorInfo{
+				HttpCode: http.StatusBadRequest,
+				Message:  util.InvalidAccountAddressErrorMessage,
+				Err:      errors.New("invalid address"),
+			}
+		}
+	}
+	return nil
+}
+
+func validatePrivateKey(privateKey string) *util.ErrorInfo {
+	if privateKey != "" && !privateKeyValidationRegex.MatchString(privateKey) {
+		return &util.ErrorInfo{
+			HttpCode: http.StatusBadRequest,
+			Message:  util.InvalidPrivateKeyErrorMessage,
+			Err:      errors.New("invalid private key"),
+		}
+	}
+	return nil
+}
+
 func validate(ctx context.Context, form interface{}) *util.ErrorInfo {
 	validate := validator.New()
 	err := validate.StructCtx(ctx, form)
@@ -49,6 +84,19 @@ func (s *Serializer) ShouldBindUri(obj interface{}) *util.ErrorInfo {
 	}
 	return nil
 }
+
+func (s *Serializer) ShouldBindQuery(obj interface{}) *util.ErrorInfo {
+	err := s.C.ShouldBindQuery(obj)
+	if err != nil {
+		return &util.ErrorInfo{
+			HttpCode: http.StatusBadRequest,
+			Err:      err,
+			Message:  util.BindingErrorMessage,
+		}
+	}
+	return nil
+}
+
 func (s *Serializer) ShouldBindJSON(obj interface{}) *util.ErrorInfo {
 	err := s.C.ShouldBindJSON(obj)
 	if err != nil {
@@ -0,0 +1,214 @@
+package serializers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"golang-ethereum-example-api/pkg/util"
+	"net/http"
+)
+
+type RegisterContractRequest struct {
+	Name    string          `json:"name" validate:"required"`
+	Address string          `json:"address" validate:"required"`
+	ABI     json.RawMessage `json:"abi" validate:"required"`
+}
+
+func (r *RegisterContractRequest) Validate(ctx context.Context) *util.ErrorInfo {
+	errInfo := validate(ctx, r)
+	if errInfo != nil {
+		return errInfo
+	}
+	errInfo = validateContractName(r.Name)
+	if errInfo != nil {
+		return errInfo
+	}
+	return validateAddresses(r.Address)
+}
+
+type GetContractRequest struct {
+	Name string `uri:"name" validate:"required"`
+}
+
+func (r *GetContractRequest) Validate(ctx context.Context) *util.ErrorInfo {
+	errInfo := validate(ctx, r)
+	if errInfo != nil {
+		return errInfo
+	}
+	return validateContractName(r.Name)
+}
+
+type ContractResponse struct {
+	Name    string          `json:"name,omitempty"`
+	Address string          `json:"address,omitempty"`
+	Methods []string        `json:"methods"`
+	Events  []string        `json:"events"`
+	ABI     json.RawMessage `json:"abi,omitempty"`
+}
+
+type CallContractRequest struct {
+	Name   string            `uri:"name" json:"-" validate:"required"`
+	Method string            `uri:"method" json:"-" validate:"required"`
+	From   string            `json:"from"`
+	Args   []json.RawMessage `json:"args"`
+	Value  string            `json:"value"`
+	Block  string            `json:"block"`
+}
+
+func (r *CallContractRequest) Validate(ctx context.Context) *util.ErrorInfo {
+	errInfo := validate(ctx, r)
+	if errInfo != nil {
+		return errInfo
+	}
+	errInfo = validateContractName(r.Name)
+	if errInfo != nil {
+		return errInfo
+	}
+	if r.From != "" {
+		errInfo = validateAddresses(r.From)
+		if errInfo != nil {
+			return errInfo
+		}
+	}
+	return validateWeiValue(r.Value)
+}
+
+type CallContractResponse struct {
+	Contract    string      `json:"contract,omitempty"`
+	Address     string      `json:"address,omitempty"`
+	Method      string      `json:"method,omitempty"`
+	BlockNumber *uint64     `json:"blockNumber,omitempty"`
+	BlockHash   string      `json:"blockHash,omitempty"`
+	Outputs     interface{} `json:"outputs"`
+}
+
+type TransactContractRequest struct {
+	Name                 string            `uri:"name" json:"-" validate:"required"`
+	Method               string            `uri:"method" json:"-" validate:"required"`
+	FromAddress          string            `json:"fromAddress" validate:"required"`
+	PrivateKey           string            `json:"privateKey"`
+	Args                 []json.RawMessage `json:"args"`
+	Value                string            `json:"value"`
+	MaxFeePerGas         string            `json:"maxFeePerGas"`
+	MaxPriorityFeePerGas string            `json:"maxPriorityFeePerGas"`
+	GasLimit             uint64            `json:"gasLimit" validate:"omitempty,min=21000"`
+	WaitConfirmations    uint64            `json:"waitConfirmations"`
+	TimeoutSeconds       uint64            `json:"timeoutSeconds" validate:"omitempty,min=1"`
+}
+
+func (r *TransactContractRequest) Validate(ctx context.Context) *util.ErrorInfo {
+	errInfo := validate(ctx, r)
+	if errInfo != nil {
+		return errInfo
+	}
+	errInfo = validateContractName(r.Name)
+	if errInfo != nil {
+		return errInfo
+	}
+	errInfo = validateAddresses(r.FromAddress)
+	if errInfo != nil {
+		return errInfo
+	}
+	errInfo = validatePrivateKey(r.PrivateKey)
+	if errInfo != nil {
+		return errInfo
+	}
+	errInfo = validateWeiValue(r.Value)
+	if errInfo != nil {
+		return errInfo
+	}
+	return validateFeeCaps(r.MaxFeePerGas, r.MaxPriorityFeePerGas)
+}
+
+type TransactContractResponse struct {
+	Contract        string `json:"contract,omitempty"`
+	Address         string `json:"address,omitempty"`
+	Method          string `json:"method,omitempty"`
+	TransactionHash string `json:"transactionHash,omitempty"`
+	Value           string `json:"value,omitempty"`
+	GasLimit        uint64 `json:"gasLimit,omitempty"`
+	TransactionFees
+	Receipt *GetTransactionResponse `json:"receipt,omitempty"`
+}
+
+type DeployContractRequest struct {
+	FromAddress          string            `json:"fromAddress" validate:"required"`
+	PrivateKey           string            `json:"privateKey"`
+	Name                 string            `json:"name" validate:"required_with=WaitConfirmations"`
+	Bytecode             string            `json:"bytecode" validate:"required"`
+	ABI                  json.RawMessage   `json:"abi" validate:"required"`
+	Args                 []json.RawMessage `json:"args"`
+	Value                string            `json:"value"`
+	MaxFeePerGas         string            `json:"maxFeePerGas"`
+	MaxPriorityFeePerGas string            `json:"maxPriorityFeePerGas"`
+	GasLimit             uint64            `json:"gasLimit" validate:"omitempty,min=53000"`
+	WaitConfirmations    uint64            `json:"waitConfirmations"`
+	TimeoutSeconds       uint64            `json:"timeoutSeconds" validate:"omitempty,min=1"`
+}
+
+func (r *DeployContractRequest) Validate(ctx context.Context) *util.ErrorInfo {
+	errInfo := validate(ctx, r)
+	if errInfo != nil {
+		return errInfo
+	}
+	if r.Name != "" {
+		errInfo = validateContractName(r.Name)
+		if errInfo != nil {
+			return errInfo
+		}
+	}
+	errInfo = validateAddresses(r.FromAddress)
+	if errInfo != nil {
+		return errInfo
+	}
+	errInfo = validatePrivateKey(r.PrivateKey)
+	if errInfo != nil {
+		return errInfo
+	}
+	if r.Bytecode == "0x" || !hexDataValidationRegex.MatchString(r.Bytecode) {
+		return &util.ErrorInfo{
+			HttpCode: http.StatusBadRequest,
+			Message:  util.ValidationErrorMessage,
+			Err:      errors.New("bytecode must be non-empty 0x prefixed hex"),
+		}
+	}
+	errInfo = validateWeiValue(r.Value)
+	if errInfo != nil {
+		return errInfo
+	}
+	return validateFeeCaps(r.MaxFeePerGas, r.MaxPriorityFeePerGas)
+}
+
+type DeployContractResponse struct {
+	Name            string `json:"name,omitempty"`
+	TransactionHash string `json:"transactionHash,omitempty"`
+	ContractAddress string `json:"contractAddress,omitempty"`
+	Deployed        bool   `json:"deployed"`
+	Registered      bool   `json:"registered"`
+	Value           string `json:"value,omitempty"`
+	GasLimit        uint64 `json:"gasLimit,omitempty"`
+	TransactionFees
+	Receipt *GetTransactionResponse `json:"receipt,omitempty"`
+}
+
+func validateContractName(name string) *util.ErrorInfo {
+	if !contractNameValidationRegex.MatchString(name) {
+		return &util.ErrorInfo{
+			HttpCode: http.StatusBadRequest,
+			Message:  util.ValidationErrorMessage,
+			Err:      errors.New("contract name must be 1-64 letters, digits, '_' or '-'"),
+		}
+	}
+	return nil
+}
+
+func validateWeiValue(value string) *util.ErrorInfo {
+	if value != "" && !integerValidationRegex.MatchString(value) {
+		return &util.ErrorInfo{
+			HttpCode: http.StatusBadRequest,
+			Message:  util.InvalidAmountErrorMessage,
+			Err:      errors.New("value must be a non-negative integer wei amount"),
+		}
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+package serializers
+
+import (
+	"context"
+	"errors"
+	"golang-ethereum-example-api/pkg/util"
+	"net/http"
+	"strings"
+)
+
+// LogFilter selects logs like eth_getLogs. Addresses may be repeated, and each topic position
+// takes a comma separated list of alternatives where an empty position matches anything.
+type LogFilter struct {
+	Address []string `form:"address"`
+	Topic0  string   `form:"topic0"`
+	Topic1  string   `form:"topic1"`
+	Topic2  string   `form:"topic2"`
+	Topic3  string   `form:"topic3"`
+}
+
+// Topics returns the topic filter with trailing wildcard positions trimmed.
+func (f *LogFilter) Topics() [][]string {
+	topics := make([][]string, 0, 4)
+	for _, topic := range []string{f.Topic0, f.Topic1, f.Topic2, f.Topic3} {
+		var alternatives []string
+		for _, hash := range strings.Split(topic, ",") {
+			hash = strings.TrimSpace(hash)
+			if hash != "" {
+				alternatives = append(alternatives, hash)
+			}
+		}
+		topics = append(topics, alternatives)
+	}
+	for len(topics) > 0 && len(topics[len(topics)-1]) == 0 {
+		topics = topics[:len(topics)-1]
+	}
+	return topics
+}
+
+func (f *LogFilter) validate() *util.ErrorInfo {
+	errInfo := validateAddresses(f.Address...)
+	if errInfo != nil {
+		return errInfo
+	}
+	for _, topic := range f.Topics() {
+		for _, hash := range topic {
+			if !hashValidationRegex.MatchString(hash) {
+				return &util.ErrorInfo{
+					HttpCode: http.StatusBadRequest,
+					Message:  util.InvalidTopicErrorMessage,
+					Err:      errors.New("topics must be 0x prefixed 32 byte hashes"),
+				}
+			}
+		}
+	}
+	return nil
+}
+
+type GetLogsRequest struct {
+	LogFilter
+	FromBlock string `form:"fromBlock"`
+	ToBlock   string `form:"toBlock"`
+	Limit     int    `form:"limit" validate:"omitempty,min=1,max=1000"`
+	Cursor    string `form:"cursor"`
+}
+
+func (r *GetLogsRequest) Validate(ctx context.Context) *util.ErrorInfo {
+	errInfo := validate(ctx, r)
+	if errInfo != nil {
+		return errInfo
+	}
+	errInfo = r.LogFilter.validate()
+	if errInfo != nil {
+		return errInfo
+	}
+	if r.Cursor != "" && !logCursorValidationRegex.MatchString(r.Cursor) {
+		return &util.ErrorInfo{
+			HttpCode: http.StatusBadRequest,
+			Message:  util.InvalidCursorErrorMessage,
+			Err:      errors.New("cursor must be <block>:<logIndex>"),
+		}
+	}
+	return nil
+}
+
+type GetLogsResponse struct {
+	FromBlock  uint64        `json:"fromBlock"`
+	ToBlock    uint64        `json:"toBlock"`
+	Logs       []LogResponse `json:"logs"`
+	NextCursor string        `json:"nextCursor,omitempty"`
+}
+
+type LogResponse struct {
+	Address          string                `json:"address,omitempty"`
+	Topics           []string              `json:"topics"`
+	Data             string                `json:"data,omitempty"`
+	BlockNumber      uint64                `json:"blockNumber"`
+	BlockHash        string                `json:"blockHash,omitempty"`
+	TransactionHash  string                `json:"transactionHash,omitempty"`
+	TransactionIndex uint                  `json:"transactionIndex"`
+	LogIndex         uint                  `json:"logIndex"`
+	Removed          bool                  `json:"removed,omitempty"`
+	Event            *DecodedEventResponse `json:"event,omitempty"`
+}
+
+type DecodedEventResponse struct {
+	Contract  string      `json:"contract,omitempty"`
+	Name      string      `json:"name,omitempty"`
+	Signature string      `json:"signature,omitempty"`
+	Args      interface{} `json:"args"`
+}
+
+type StreamLogsRequest struct {
+	LogFilter
+}
+
+func (r *StreamLogsRequest) Validate(ctx context.Context) *util.ErrorInfo {
+	errInfo := validate(ctx, r)
+	if errInfo != nil {
+		return errInfo
+	}
+	return r.LogFilter.validate()
+}
+
+type HeadResponse struct {
+	Number        uint64 `json:"number"`
+	Hash          string `json:"hash,omitempty"`
+	ParentHash    string `json:"parentHash,omitempty"`
+	Timestamp     uint64 `json:"timestamp"`
+	GasLimit      uint64 `json:"gasLimit"`
+	GasUsed       uint64 `json:"gasUsed"`
+	BaseFeePerGas string `json:"baseFeePerGas,omitempty"`
+}
@@ -0,0 +1,278 @@
+package serializers
+
+import (
+	"context"
+	"errors"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"golang-ethereum-example-api/pkg/util"
+	"math/big"
+	"net/http"
+)
+
+type GetNftInterfacesRequest struct {
+	Contract string `uri:"contract" validate:"required"`
+}
+
+func (r *GetNftInterfacesRequest) Validate(ctx context.Context) *util.ErrorInfo {
+	errInfo := validate(ctx, r)
+	if errInfo != nil {
+		return errInfo
+	}
+	return validateAddresses(r.Contract)
+}
+
+type GetNftInterfacesResponse struct {
+	Contract           string `json:"contract,omitempty"`
+	Erc165             bool   `json:"erc165"`
+	Erc721             bool   `json:"erc721"`
+	Erc721Metadata     bool   `json:"erc721Metadata"`
+	Erc1155            bool   `json:"erc1155"`
+	Erc1155MetadataURI bool   `json:"erc1155MetadataUri"`
+}
+
+type GetNftTokenRequest struct {
+	Contract string `uri:"contract" validate:"required"`
+	TokenId  string `uri:"tokenId" validate:"required"`
+}
+
+func (r *GetNftTokenRequest) Validate(ctx context.Context) *util.ErrorInfo {
+	errInfo := validate(ctx, r)
+	if errInfo != nil {
+		return errInfo
+	}
+	errInfo = validateAddresses(r.Contract)
+	if errInfo != nil {
+		return errInfo
+	}
+	return validateTokenIds(r.TokenId)
+}
+
+type GetNftOwnerResponse struct {
+	Contract string `json:"contract,omitempty"`
+	TokenId  string `json:"tokenId,omitempty"`
+	Owner    string `json:"owner,omitempty"`
+}
+
+type GetNftTokenURIResponse struct {
+	Contract string `json:"contract,omitempty"`
+	TokenId  string `json:"tokenId,omitempty"`
+	Standard string `json:"standard,omitempty"`
+	URI      string `json:"uri"`
+}
+
+type GetNftBalanceRequest struct {
+	Address  string `uri:"address" validate:"required"`
+	Contract string `uri:"contract" validate:"required"`
+	TokenId  string `form:"tokenId"`
+}
+
+func (r *GetNftBalanceRequest) Validate(ctx context.Context) *util.ErrorInfo {
+	errInfo := validate(ctx, r)
+	if errInfo != nil {
+		return errInfo
+	}
+	errInfo = validateAddresses(r.Address, r.Contract)
+	if errInfo != nil {
+		return errInfo
+	}
+	if r.TokenId == "" {
+		return nil
+	}
+	return validateTokenIds(r.TokenId)
+}
+
+type GetNftBalanceResponse struct {
+	Address  string `json:"address,omitempty"`
+	Contract string `json:"contract,omitempty"`
+	TokenId  string `json:"tokenId,omitempty"`
+	Standard string `json:"standard,omitempty"`
+	Balance  string `json:"balance,omitempty"`
+}
+
+type TransferErc721Request struct {
+	FromAddress          string `json:"fromAddress" validate:"required"`
+	PrivateKey           string `json:"privateKey"`
+	ContractAddress      string `json:"contractAddress" validate:"required"`
+	ToAddress            string `json:"toAddress" validate:"required"`
+	TokenId              string `json:"tokenId" validate:"required"`
+	Data                 string `json:"data"`
+	MaxFeePerGas         string `json:"maxFeePerGas"`
+	MaxPriorityFeePerGas string `json:"maxPriorityFeePerGas"`
+	GasLimit             uint64 `json:"gasLimit" validate:"omitempty,min=21000"`
+	WaitConfirmations    uint64 `json:"waitConfirmations"`
+	TimeoutSeconds       uint64 `json:"timeoutSeconds" validate:"omitempty,min=1"`
+}
+
+func (r *TransferErc721Request) Validate(ctx context.Context) *util.ErrorInfo {
+	errInfo := validate(ctx, r)
+	if errInfo != nil {
+		return errInfo
+	}
+	errInfo = validateAddresses(r.FromAddress, r.ContractAddress, r.ToAddress)
+	if errInfo != nil {
+		return errInfo
+	}
+	errInfo = validatePrivateKey(r.PrivateKey)
+	if errInfo != nil {
+		return errInfo
+	}
+	errInfo = validateTokenIds(r.TokenId)
+	if errInfo != nil {
+		return errInfo
+	}
+	errInfo = validateHexData(r.Data)
+	if errInfo != nil {
+		return errInfo
+	}
+	return validateFeeCaps(r.MaxFeePerGas, r.MaxPriorityFeePerGas)
+}
+
+type TransferErc1155Request struct {
+	FromAddress          string `json:"fromAddress" validate:"required"`
+	PrivateKey           string `json:"privateKey"`
+	ContractAddress      string `json:"contractAddress" validate:"required"`
+	ToAddress            string `json:"toAddress" validate:"required"`
+	TokenId              string `json:"tokenId" validate:"required"`
+	Amount               string `json:"amount" validate:"required"`
+	Data                 string `json:"data"`
+	MaxFeePerGas         string `json:"maxFeePerGas"`
+	MaxPriorityFeePerGas string `json:"maxPriorityFeePerGas"`
+	GasLimit             uint64 `json:"gasLimit" validate:"omitempty,min=21000"`
+	WaitConfirmations    uint64 `json:"waitConfirmations"`
+	TimeoutSeconds       uint64 `json:"timeoutSeconds" validate:"omitempty,min=1"`
+}
+
+func (r *TransferErc1155Request) Validate(ctx context.Context) *util.ErrorInfo {
+	errInfo := validate(ctx, r)
+	if errInfo != nil {
+		return errInfo
+	}
+	errInfo = validateAddresses(r.FromAddress, r.ContractAddress, r.ToAddress)
+	if errInfo != nil {
+		return errInfo
+	}
+	errInfo = validatePrivateKey(r.PrivateKey)
+	if errInfo != nil {
+		return errInfo
+	}
+	errInfo = validateTokenIds(r.TokenId)
+	if errInfo != nil {
+		return errInfo
+	}
+	errInfo = validateNftAmounts(r.Amount)
+	if errInfo != nil {
+		return errInfo
+	}
+	errInfo = validateHexData(r.Data)
+	if errInfo != nil {
+		return errInfo
+	}
+	return validateFeeCaps(r.MaxFeePerGas, r.MaxPriorityFeePerGas)
+}
+
+type TransferErc1155BatchRequest struct {
+	FromAddress          string   `json:"fromAddress" validate:"required"`
+	PrivateKey           string   `json:"privateKey"`
+	ContractAddress      string   `json:"contractAddress" validate:"required"`
+	ToAddress            string   `json:"toAddress" validate:"required"`
+	TokenIds             []string `json:"tokenIds" validate:"required,min=1"`
+	Amounts              []string `json:"amounts" validate:"required,min=1"`
+	Data                 string   `json:"data"`
+	MaxFeePerGas         string   `json:"maxFeePerGas"`
+	MaxPriorityFeePerGas string   `json:"maxPriorityFeePerGas"`
+	GasLimit             uint64   `json:"gasLimit" validate:"omitempty,min=21000"`
+	WaitConfirmations    uint64   `json:"waitConfirmations"`
+	TimeoutSeconds       uint64   `json:"timeoutSeconds" validate:"omitempty,min=1"`
+}
+
+func (r *TransferErc1155BatchRequest) Validate(ctx context.Context) *util.ErrorInfo {
+	errInfo := validate(ctx, r)
+	if errInfo != nil {
+		return errInfo
+	}
+	errInfo = validateAddresses(r.FromAddress, r.ContractAddress, r.ToAddress)
+	if errInfo != nil {
+		return errInfo
+	}
+	errInfo = validatePrivateKey(r.PrivateKey)
+	if errInfo != nil {
+		return errInfo
+	}
+	if len(r.TokenIds) != len(r.Amounts) {
+		return &util.ErrorInfo{
+			HttpCode: http.StatusBadRequest,
+			Message:  util.ValidationErrorMessage,
+			Err:      errors.New("tokenIds and amounts must have the same length"),
+		}
+	}
+	errInfo = validateTokenIds(r.TokenIds...)
+	if errInfo != nil {
+		return errInfo
+	}
+	errInfo = validateNftAmounts(r.Amounts...)
+	if errInfo != nil {
+		return errInfo
+	}
+	errInfo = validateHexData(r.Data)
+	if errInfo != nil {
+		return errInfo
+	}
+	return validateFeeCaps(r.MaxFeePerGas, r.MaxPriorityFeePerGas)
+}
+
+type TransferNftResponse struct {
+	TransactionHash string   `json:"transactionHash,omitempty"`
+	ContractAddress string   `json:"contractAddress,omitempty"`
+	Standard        string   `json:"standard,omitempty"`
+	TokenIds        []string `json:"tokenIds,omitempty"`
+	Amounts         []string `json:"amounts,omitempty"`
+	GasLimit        uint64   `json:"gasLimit,omitempty"`
+	TransactionFees
+	Receipt *GetTransactionResponse `json:"receipt,omitempty"`
+}
+
+func validateTokenIds(tokenIds ...string) *util.ErrorInfo {
+	for _, tokenId := range tokenIds {
+		if !isUint256(tokenId) {
+			return &util.ErrorInfo{
+				HttpCode: http.StatusBadRequest,
+				Message:  util.InvalidTokenIdErrorMessage,
+				Err:      errors.New("token id must be an integer between 0 and 2^256-1"),
+			}
+		}
+	}
+	return nil
+}
+
+func validateNftAmounts(amounts ...string) *util.ErrorInfo {
+	for _, amount := range amounts {
+		if !isUint256(amount) {
+			return &util.ErrorInfo{
+				HttpCode: http.StatusBadRequest,
+				Message:  util.InvalidAmountErrorMessage,
+				Err:      errors.New("amount must be an integer between 0 and 2^256-1"),
+			}
+		}
+	}
+	return nil
+}
+
+// isUint256 reports whether value is a decimal integer that fits a uint256 ABI argument.
+func isUint256(value string) bool {
+	if !integerValidationRegex.MatchString(value) {
+		return false
+	}
+	parsed, ok := new(big.Int).SetString(value, 10)
+	return ok && parsed.Cmp(abi.MaxUint256) <= 0
+}
+
+func validateHexData(data string) *util.ErrorInfo {
+	if data != "" && !hexDataValidationRegex.MatchString(data) {
+		return &util.ErrorInfo{
+			HttpCode: http.StatusBadRequest,
+			Message:  util.ValidationErrorMessage,
+			Err:      errors.New("data must be 0x prefixed hex"),
+		}
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package serializers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"golang-ethereum-example-api/pkg/util"
+	"net/http"
+)
+
+// SendRawTransactionRequest takes either a signed transaction, or an unsigned transaction
+// from /transfer/prepare together with its signature.
+type SendRawTransactionRequest struct {
+	RawTransaction      string  `json:"rawTransaction" validate:"required_without=UnsignedTransaction,excluded_with=UnsignedTransaction"`
+	UnsignedTransaction string  `json:"unsignedTransaction"`
+	R                   string  `json:"r" validate:"required_with=UnsignedTransaction"`
+	S                   string  `json:"s" validate:"required_with=UnsignedTransaction"`
+	V                   *uint64 `json:"v" validate:"required_with=UnsignedTransaction"`
+	WaitConfirmations   uint64  `json:"waitConfirmations"`
+	TimeoutSeconds      uint64  `json:"timeoutSeconds" validate:"omitempty,min=1"`
+}
+
+func (r *SendRawTransactionRequest) Validate(ctx context.Context) *util.ErrorInfo {
+	errInfo := validate(ctx, r)
+	if errInfo != nil {
+		return errInfo
+	}
+	for _, data := range []string{r.RawTransaction, r.UnsignedTransaction} {
+		if data != "" && !hexDataValidationRegex.MatchString(data) {
+			return &util.ErrorInfo{
+				HttpCode: http.StatusBadRequest,
+				Message:  util.InvalidRawTransactionErrorMessage,
+				Err:      errors.New("transaction must be 0x prefixed hex"),
+			}
+		}
+	}
+	for _, value := range []string{r.R, r.S} {
+		if value != "" && !signatureValueValidationRegex.MatchString(value) {
+			return &util.ErrorInfo{
+				HttpCode: http.StatusBadRequest,
+				Message:  util.InvalidSignatureErrorMessage,
+				Err:      errors.New("r and s must be 0x prefixed hex of at most 32 bytes"),
+			}
+		}
+	}
+	return nil
+}
+
+type SendRawTransactionResponse struct {
+	TransactionHash string `json:"transactionHash"`
+	From            string `json:"from"`
+	To              string `json:"to,omitempty"`
+	ChainId         string `json:"chainId"`
+	Nonce           uint64 `json:"nonce"`
+	Value           string `json:"value"`
+	GasLimit        uint64 `json:"gasLimit"`
+	Data            string `json:"data,omitempty"`
+	TransactionFees
+	Receipt *GetTransactionResponse `json:"receipt,omitempty"`
+}
+
+type PrepareTransferRequest struct {
+	FromAddress          string `json:"fromAddress" validate:"required"`
+	ToAddress            string `json:"toAddress" validate:"required"`
+	EthereumAmount       string `json:"ethereumAmount" validate:"required"`
+	Unit                 string `json:"unit" validate:"omitempty,oneof=ether gwei wei"`
+	MaxFeePerGas         string `json:"maxFeePerGas"`
+	MaxPriorityFeePerGas string `json:"maxPriorityFeePerGas"`
+	GasLimit             uint64 `json:"gasLimit" validate:"omitempty,min=21000"`
+}
+
+func (r *PrepareTransferRequest) Validate(ctx context.Context) *util.ErrorInfo {
+	errInfo := validate(ctx, r)
+	if errInfo != nil {
+		return errInfo
+	}
+	errInfo = validateAddresses(r.FromAddress, r.ToAddress)
+	if errInfo != nil {
+		return errInfo
+	}
+	errInfo = validateAmount(r.EthereumAmount, r.Unit)
+	if errInfo != nil {
+		return errInfo
+	}
+	return validateFeeCaps(r.MaxFeePerGas, r.MaxPriorityFeePerGas)
+}
+
+// PrepareTransferResponse carries an unsigned transaction. SigningHash is what the offline
+// signer signs, the resulting r, s and v are sent back with UnsignedTransaction.
+type PrepareTransferResponse struct {
+	From                string          `json:"from"`
+	To                  string          `json:"to"`
+	ChainId             string          `json:"chainId"`
+	Nonce               uint64          `json:"nonce"`
+	Value               string          `json:"value"`
+	GasLimit            uint64          `json:"gasLimit"`
+	UnsignedTransaction string          `json:"unsignedTransaction"`
+	Transaction         json.RawMessage `json:"transaction"`
+	SigningHash         string          `json:"signingHash"`
+	TransactionFees
+}
@@ -0,0 +1,47 @@
+package serializers
+
+import (
+	"context"
+	"errors"
+	"golang-ethereum-example-api/pkg/util"
+	"net/http"
+)
+
+// ReplaceTransactionRequest speeds up or cancels a pending transaction. Fee caps left empty
+// are raised to the minimum the node accepts for a replacement.
+type ReplaceTransactionRequest struct {
+	Hash                 string `uri:"hash" json:"-" validate:"required"`
+	PrivateKey           string `json:"privateKey"`
+	MaxFeePerGas         string `json:"maxFeePerGas"`
+	MaxPriorityFeePerGas string `json:"maxPriorityFeePerGas"`
+	WaitConfirmations    uint64 `json:"waitConfirmations"`
+	TimeoutSeconds       uint64 `json:"timeoutSeconds" validate:"omitempty,min=1"`
+}
+
+func (r *ReplaceTransactionRequest) Validate(ctx context.Context) *util.ErrorInfo {
+	errInfo := validate(ctx, r)
+	if errInfo != nil {
+		return errInfo
+	}
+	if !hashValidationRegex.MatchString(r.Hash) {
+		return &util.ErrorInfo{
+			HttpCode: http.StatusBadRequest,
+			Message:  util.InvalidTransactionHashErrorMessage,
+			Err:      errors.New("invalid transaction hash"),
+		}
+	}
+	errInfo = validatePrivateKey(r.PrivateKey)
+	if errInfo != nil {
+		return errInfo
+	}
+	return validateFeeCaps(r.MaxFeePerGas, r.MaxPriorityFeePerGas)
+}
+
+type ReplaceTransactionResponse struct {
+	OriginalTransactionHash string `json:"originalTransactionHash"`
+	TransactionHash         string `json:"transactionHash"`
+	Nonce                   uint64 `json:"nonce"`
+	GasLimit                uint64 `json:"gasLimit"`
+	TransactionFees
+	Receipt *GetTransactionResponse `json:"receipt,omitempty"`
+}
@@ -4,14 +4,21 @@ import (
 	"context"
 	"errors"
 	"golang-ethereum-example-api/pkg/util"
+	"math/big"
 	"net/http"
 )
 
 type SendEthereumRequest struct {
-	FromAddress    string  `json:"fromAddress" validate:"required"`
-	PrivateKey     string  `json:"privateKey" validate:"required"`
-	ToAddress      string  `json:"toAddress" validate:"required"`
-	EthereumAmount float64 `json:"ethereumAmount" validate:"required"`
+	FromAddress          string `json:"fromAddress" validate:"required"`
+	PrivateKey           string `json:"privateKey"`
+	ToAddress            string `json:"toAddress" validate:"required"`
+	EthereumAmount       string `json:"ethereumAmount" validate:"required"`
+	Unit                 string `json:"unit" validate:"omitempty,oneof=ether gwei wei"`
+	MaxFeePerGas         string `json:"maxFeePerGas"`
+	MaxPriorityFeePerGas string `json:"maxPriorityFeePerGas"`
+	GasLimit             uint64 `json:"gasLimit" validate:"omitempty,min=21000"`
+	WaitConfirmations    uint64 `json:"waitConfirmations"`
+	TimeoutSeconds       uint64 `json:"timeoutSeconds" validate:"omitempty,min=1"`
 }
 
 func (r *SendEthereumRequest) Validate(ctx context.Context) *util.ErrorInfo {
@@ -19,18 +26,243 @@ func (r *SendEthereumRequest) Validate(ctx context.Context) *util.ErrorInfo {
 	if errInfo != nil {
 		return errInfo
 	}
-	isValidToAddress := addressValidationRegex.MatchString(r.ToAddress)
-	isValidFromAddress := addressValidationRegex.MatchString(r.FromAddress)
-	if !isValidFromAddress || !isValidToAddress {
+	errInfo = validateAddresses(r.FromAddress, r.ToAddress)
+	if errInfo != nil {
+		return errInfo
+	}
+	errInfo = validatePrivateKey(r.PrivateKey)
+	if errInfo != nil {
+		return errInfo
+	}
+	errInfo = validateAmount(r.EthereumAmount, r.Unit)
+	if errInfo != nil {
+		return errInfo
+	}
+	return validateFeeCaps(r.MaxFeePerGas, r.MaxPriorityFeePerGas)
+}
+
+type TransactionFees struct {
+	TransactionType      string `json:"transactionType,omitempty"`
+	GasPrice             string `json:"gasPrice,omitempty"`
+	MaxFeePerGas         string `json:"maxFeePerGas,omitempty"`
+	MaxPriorityFeePerGas string `json:"maxPriorityFeePerGas,omitempty"`
+}
+
+type SendEthereumResponse struct {
+	TransactionHash string `json:"transactionHash,omitempty"`
+	Value           string `json:"value,omitempty"`
+	GasLimit        uint64 `json:"gasLimit,omitempty"`
+	TransactionFees
+	Receipt *GetTransactionResponse `json:"receipt,omitempty"`
+}
+
+type SendTokenRequest struct {
+	FromAddress          string `json:"fromAddress" validate:"required"`
+	PrivateKey           string `json:"privateKey"`
+	TokenAddress         string `json:"tokenAddress" validate:"required"`
+	ToAddress            string `json:"toAddress" validate:"required"`
+	Amount               string `json:"amount" validate:"required"`
+	MaxFeePerGas         string `json:"maxFeePerGas"`
+	MaxPriorityFeePerGas string `json:"maxPriorityFeePerGas"`
+	GasLimit             uint64 `json:"gasLimit" validate:"omitempty,min=21000"`
+	WaitConfirmations    uint64 `json:"waitConfirmations"`
+	TimeoutSeconds       uint64 `json:"timeoutSeconds" validate:"omitempty,min=1"`
+}
+
+func (r *SendTokenRequest) Validate(ctx context.Context) *util.ErrorInfo {
+	errInfo := validate(ctx, r)
+	if errInfo != nil {
+		return errInfo
+	}
+	errInfo = validateAddresses(r.FromAddress, r.TokenAddress, r.ToAddress)
+	if errInfo != nil {
+		return errInfo
+	}
+	errInfo = validatePrivateKey(r.PrivateKey)
+	if errInfo != nil {
+		return errInfo
+	}
+	if !decimalValidationRegex.MatchString(r.Amount) {
 		return &util.ErrorInfo{
 			HttpCode: http.StatusBadRequest,
-			Message:  util.InvalidAccountAddressErrorMessage,
-			Err:      errors.New("invalid address"),
+			Message:  util.InvalidAmountErrorMessage,
+			Err:      errors.New("amount is not a decimal number"),
 		}
 	}
-	return nil
+	return validateFeeCaps(r.MaxFeePerGas, r.MaxPriorityFeePerGas)
 }
 
-type SendEthereumResponse struct {
+type SendTokenResponse struct {
 	TransactionHash string `json:"transactionHash,omitempty"`
+	TokenAddress    string `json:"tokenAddress,omitempty"`
+	Symbol          string `json:"symbol,omitempty"`
+	Amount          string `json:"amount,omitempty"`
+	Value           string `json:"value,omitempty"`
+	GasLimit        uint64 `json:"gasLimit,omitempty"`
+	TransactionFees
+	Receipt *GetTransactionResponse `json:"receipt,omitempty"`
+}
+
+type ApproveTokenRequest struct {
+	FromAddress          string `json:"fromAddress" validate:"required"`
+	PrivateKey           string `json:"privateKey"`
+	TokenAddress         string `json:"tokenAddress" validate:"required"`
+	SpenderAddress       string `json:"spenderAddress" validate:"required"`
+	Amount               string `json:"amount" validate:"required_without=Unlimited,excluded_with=Unlimited"`
+	Unlimited            bool   `json:"unlimited"`
+	SafeApprove          bool   `json:"safeApprove"`
+	MaxFeePerGas         string `json:"maxFeePerGas"`
+	MaxPriorityFeePerGas string `json:"maxPriorityFeePerGas"`
+	GasLimit             uint64 `json:"gasLimit" validate:"omitempty,min=21000"`
+	WaitConfirmations    uint64 `json:"waitConfirmations"`
+	TimeoutSeconds       uint64 `json:"timeoutSeconds" validate:"omitempty,min=1"`
+}
+
+func (r *ApproveTokenRequest) Validate(ctx context.Context) *util.ErrorInfo {
+	errInfo := validate(ctx, r)
+	if errInfo != nil {
+		return errInfo
+	}
+	errInfo = validateAddresses(r.FromAddress, r.TokenAddress, r.SpenderAddress)
+	if errInfo != nil {
+		return errInfo
+	}
+	errInfo = validatePrivateKey(r.PrivateKey)
+	if errInfo != nil {
+		return errInfo
+	}
+	if !r.Unlimited && !decimalValidationRegex.MatchString(r.Amount) {
+		return &util.ErrorInfo{
+			HttpCode: http.StatusBadRequest,
+			Message:  util.InvalidAmountErrorMessage,
+			Err:      errors.New("amount is not a decimal number"),
+		}
+	}
+	return validateFeeCaps(r.MaxFeePerGas, r.MaxPriorityFeePerGas)
+}
+
+type ApproveTokenResponse struct {
+	TransactionHash      string `json:"transactionHash,omitempty"`
+	ResetTransactionHash string `json:"resetTransactionHash,omitempty"`
+	TokenAddress         string `json:"tokenAddress,omitempty"`
+	SpenderAddress       string `json:"spenderAddress,omitempty"`
+	Symbol               string `json:"symbol,omitempty"`
+	Amount               string `json:"amount,omitempty"`
+	Value                string `json:"value,omitempty"`
+	Unlimited            bool   `json:"unlimited"`
+	GasLimit             uint64 `json:"gasLimit,omitempty"`
+	TransactionFees
+	Receipt *GetTransactionResponse `json:"receipt,omitempty"`
+}
+
+type EstimateTransferRequest struct {
+	FromAddress          string `json:"fromAddress" validate:"required"`
+	ToAddress            string `json:"toAddress" validate:"required"`
+	EthereumAmount       string `json:"ethereumAmount" validate:"required"`
+	Unit                 string `json:"unit" validate:"omitempty,oneof=ether gwei wei"`
+	MaxFeePerGas         string `json:"maxFeePerGas"`
+	MaxPriorityFeePerGas string `json:"maxPriorityFeePerGas"`
+	GasLimit             uint64 `json:"gasLimit" validate:"omitempty,min=21000"`
+}
+
+func (r *EstimateTransferRequest) Validate(ctx context.Context) *util.ErrorInfo {
+	errInfo := validate(ctx, r)
+	if errInfo != nil {
+		return errInfo
+	}
+	errInfo = validateAddresses(r.FromAddress, r.ToAddress)
+	if errInfo != nil {
+		return errInfo
+	}
+	errInfo = validateAmount(r.EthereumAmount, r.Unit)
+	if errInfo != nil {
+		return errInfo
+	}
+	return validateFeeCaps(r.MaxFeePerGas, r.MaxPriorityFeePerGas)
+}
+
+type EstimateTransferResponse struct {
+	GasLimit uint64 `json:"gasLimit,omitempty"`
+	Value    string `json:"value,omitempty"`
+	MaxCost  string `json:"maxCost,omitempty"`
+	TransactionFees
+}
+
+type GetTransactionRequest struct {
+	Hash string `uri:"hash" validate:"required"`
+}
+
+func (r *GetTransactionRequest) Validate(ctx context.Context) *util.ErrorInfo {
+	errInfo := validate(ctx, r)
+	if errInfo != nil {
+		return errInfo
+	}
+	isValid := hashValidationRegex.MatchString(r.Hash)
+	if !isValid {
+		return &util.ErrorInfo{
+			HttpCode: http.StatusBadRequest,
+			Message:  util.InvalidTransactionHashErrorMessage,
+			Err:      errors.New("invalid transaction hash"),
+		}
+	}
+	return nil
+}
+
+type GetTransactionResponse struct {
+	TransactionHash   string  `json:"transactionHash,omitempty"`
+	Status            string  `json:"status,omitempty"`
+	TransactionType   string  `json:"transactionType,omitempty"`
+	From              string  `json:"from,omitempty"`
+	To                string  `json:"to,omitempty"`
+	Value             string  `json:"value,omitempty"`
+	Nonce             uint64  `json:"nonce"`
+	BlockNumber       *uint64 `json:"blockNumber,omitempty"`
+	BlockHash         string  `json:"blockHash,omitempty"`
+	Confirmations     uint64  `json:"confirmations"`
+	GasUsed           uint64  `json:"gasUsed,omitempty"`
+	EffectiveGasPrice string  `json:"effectiveGasPrice,omitempty"`
+	ContractAddress   string  `json:"contractAddress,omitempty"`
+}
+
+func validateFeeCaps(maxFeePerGas string, maxPriorityFeePerGas string) *util.ErrorInfo {
+	for _, fee := range []string{maxFeePerGas, maxPriorityFeePerGas} {
+		if fee != "" && !integerValidationRegex.MatchString(fee) {
+			return &util.ErrorInfo{
+				HttpCode: http.StatusBadRequest,
+				Message:  util.InvalidFeeErrorMessage,
+				Err:      errors.New("fee must be a non-negative integer wei amount"),
+			}
+		}
+	}
+	if maxFeePerGas != "" && maxPriorityFeePerGas != "" {
+		maxFee, _ := new(big.Int).SetString(maxFeePerGas, 10)
+		maxPriorityFee, _ := new(big.Int).SetString(maxPriorityFeePerGas, 10)
+		if maxFee.Cmp(maxPriorityFee) < 0 {
+			return &util.ErrorInfo{
+				HttpCode: http.StatusBadRequest,
+				Message:  util.InvalidFeeErrorMessage,
+				Err:      errors.New("maxPriorityFeePerGas is higher than maxFeePerGas"),
+			}
+		}
+	}
+	return nil
+}
+
+func validateAmount(amount string, unit string) *util.ErrorInfo {
+	value, err := util.ParseAmount(amount, unit)
+	if err != nil {
+		return &util.ErrorInfo{
+			HttpCode: http.StatusBadRequest,
+			Message:  util.InvalidAmountErrorMessage,
+			Err:      err,
+		}
+	}
+	if value.Sign() == 0 {
+		return &util.ErrorInfo{
+			HttpCode: http.StatusBadRequest,
+			Message:  util.InvalidAmountErrorMessage,
+			Err:      errors.New("amount must be greater than zero"),
+		}
+	}
+	return nil
 }
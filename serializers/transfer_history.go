@@ -0,0 +1,69 @@
+package serializers
+
+import (
+	"context"
+	"encoding/json"
+	"golang-ethereum-example-api/pkg/util"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type ListTransfersRequest struct {
+	Address string `form:"address"`
+	Status  string `form:"status" validate:"omitempty,oneof=submitting pending rejected mined confirmed failed dropped replaced"`
+	Limit   int    `form:"limit" validate:"omitempty,min=1,max=100"`
+	Cursor  string `form:"cursor"`
+}
+
+func (r *ListTransfersRequest) Validate(ctx context.Context) *util.ErrorInfo {
+	errInfo := validate(ctx, r)
+	if errInfo != nil {
+		return errInfo
+	}
+	if r.Address != "" {
+		errInfo = validateAddresses(r.Address)
+		if errInfo != nil {
+			return errInfo
+		}
+	}
+	if r.Cursor != "" {
+		_, err := strconv.ParseUint(r.Cursor, 10, 64)
+		if err != nil {
+			return &util.ErrorInfo{
+				HttpCode: http.StatusBadRequest,
+				Message:  util.InvalidCursorErrorMessage,
+				Err:      err,
+			}
+		}
+	}
+	return nil
+}
+
+type ListTransfersResponse struct {
+	Transfers  []TransferRecordResponse `json:"transfers"`
+	NextCursor string                   `json:"nextCursor,omitempty"`
+}
+
+type TransferRecordResponse struct {
+	Id             uint64                         `json:"id"`
+	Kind           string                         `json:"kind"`
+	From           string                         `json:"from"`
+	To             string                         `json:"to,omitempty"`
+	Value          string                         `json:"value"`
+	Nonce          uint64                         `json:"nonce"`
+	Hash           string                         `json:"hash"`
+	RawTransaction string                         `json:"rawTransaction"`
+	Request        json.RawMessage                `json:"request,omitempty"`
+	Status         string                         `json:"status"`
+	BlockNumber    *uint64                        `json:"blockNumber,omitempty"`
+	History        []TransferStatusChangeResponse `json:"history"`
+	CreatedAt      time.Time                      `json:"createdAt"`
+	UpdatedAt      time.Time                      `json:"updatedAt"`
+}
+
+type TransferStatusChangeResponse struct {
+	Status string    `json:"status"`
+	Detail string    `json:"detail,omitempty"`
+	At     time.Time `json:"at"`
+}
@@ -0,0 +1,65 @@
+package serializers
+
+import (
+	"context"
+	"golang-ethereum-example-api/pkg/util"
+	"time"
+)
+
+type WatchAddressRequest struct {
+	Address    string `uri:"address" json:"-" validate:"required"`
+	WebhookUrl string `json:"webhookUrl" validate:"omitempty,url"`
+}
+
+func (r *WatchAddressRequest) Validate(ctx context.Context) *util.ErrorInfo {
+	errInfo := validate(ctx, r)
+	if errInfo != nil {
+		return errInfo
+	}
+	return validateAddresses(r.Address)
+}
+
+type UnwatchAddressRequest struct {
+	Address string `uri:"address" validate:"required"`
+}
+
+func (r *UnwatchAddressRequest) Validate(ctx context.Context) *util.ErrorInfo {
+	errInfo := validate(ctx, r)
+	if errInfo != nil {
+		return errInfo
+	}
+	return validateAddresses(r.Address)
+}
+
+type WatchResponse struct {
+	Address    string    `json:"address,omitempty"`
+	WebhookUrl string    `json:"webhookUrl,omitempty"`
+	CreatedAt  time.Time `json:"createdAt"`
+	Note       string    `json:"note,omitempty"`
+}
+
+type WatchListResponse struct {
+	LastScannedBlock *uint64         `json:"lastScannedBlock,omitempty"`
+	Addresses        []WatchResponse `json:"addresses"`
+	Note             string          `json:"note,omitempty"`
+}
+
+// WebhookEvent is the body POSTed to webhooks for every confirmed deposit. Id is stable across
+// retries so receivers can deduplicate deliveries.
+type WebhookEvent struct {
+	Id              string `json:"id"`
+	Type            string `json:"type"`
+	Address         string `json:"address"`
+	From            string `json:"from,omitempty"`
+	To              string `json:"to"`
+	Value           string `json:"value"`
+	Token           string `json:"token,omitempty"`
+	Symbol          string `json:"symbol,omitempty"`
+	Decimals        *uint8 `json:"decimals,omitempty"`
+	FormattedValue  string `json:"formattedValue,omitempty"`
+	TransactionHash string `json:"transactionHash"`
+	LogIndex        *uint  `json:"logIndex,omitempty"`
+	BlockNumber     uint64 `json:"blockNumber"`
+	BlockHash       string `json:"blockHash"`
+	Confirmations   uint64 `json:"confirmations"`
+}
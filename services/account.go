@@ -2,38 +2,62 @@ package services
 
 import (
 	"context"
-	"crypto/ecdsa"
 	"errors"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
 	"go.uber.org/zap"
 	"golang-ethereum-example-api/pkg/logging"
+	"golang-ethereum-example-api/pkg/settings"
 	"golang-ethereum-example-api/pkg/util"
 	"golang-ethereum-example-api/serializers"
-	"math"
 	"math/big"
 	"net/http"
 )
 
 type AccountService interface {
 	GetBalance(ctx context.Context, request serializers.GetBalanceRequest) (*serializers.GetBalanceResponse, *util.ErrorInfo)
+	GetBalances(ctx context.Context, request serializers.GetBalancesRequest) (*serializers.GetBalancesResponse, *util.ErrorInfo)
+	GetTokenBalance(ctx context.Context, request serializers.GetTokenBalanceRequest) (*serializers.GetTokenBalanceResponse, *util.ErrorInfo)
+	GetTokenAllowance(ctx context.Context, request serializers.GetTokenAllowanceRequest) (*serializers.GetTokenAllowanceResponse, *util.ErrorInfo)
 	CreateAccount() (*serializers.CreateAccountResponse, *util.ErrorInfo)
 }
 
 type accountService struct {
-	client *ethclient.Client
-	logger *logging.LogWrapper
+	client   *ethclient.Client
+	keyStore *keystore.KeyStore
+	tokens   TokenMetadataCache
+	config   *settings.Wallet
+	logger   *logging.LogWrapper
 }
 
-func NewAccountService(client *ethclient.Client, logger *logging.LogWrapper) AccountService {
-	return &accountService{client: client, logger: logger}
+func NewAccountService(client *ethclient.Client, keyStore *keystore.KeyStore, tokens TokenMetadataCache, config *settings.Wallet, logger *logging.LogWrapper) AccountService {
+	return &accountService{client: client, keyStore: keyStore, tokens: tokens, config: config, logger: logger}
 }
 
 func (s *accountService) GetBalance(ctx context.Context, request serializers.GetBalanceRequest) (*serializers.GetBalanceResponse, *util.ErrorInfo) {
 	address := common.HexToAddress(request.Address)
-	balance, err := s.client.BalanceAt(ctx, address, nil)
+	blockRef, header, errInfo := resolveBlock(ctx, s.client, s.logger, request.Block)
+	if errInfo != nil {
+		return nil, errInfo
+	}
+
+	// Tags are read at the resolved block number, so the reported block is the one actually read.
+	var balance *big.Int
+	var err error
+	switch {
+	case blockRef.Hash != nil:
+		balance, err = s.client.BalanceAtHash(ctx, address, *blockRef.Hash)
+	case blockRef.IsPending():
+		balance, err = s.client.BalanceAt(ctx, address, blockRef.Number)
+	default:
+		balance, err = s.client.BalanceAt(ctx, address, header.Number)
+	}
 	if err != nil {
 		s.logger.Error("GetBalance getting balance error", zap.Error(err), zap.String("address", request.Address))
 		return nil, &util.ErrorInfo{
@@ -43,16 +67,153 @@ func (s *accountService) GetBalance(ctx context.Context, request serializers.Get
 		}
 	}
 
-	var fbalance = new(big.Float)
-	fbalance.SetString(balance.String())
-	ethValue := new(big.Float).Quo(fbalance, big.NewFloat(math.Pow10(18)))
-	return &serializers.GetBalanceResponse{
-		EthValue: ethValue,
+	blockNumber := header.Number.Uint64()
+	response := &serializers.GetBalanceResponse{
+		Address:     request.Address,
+		Wei:         balance.String(),
+		EthValue:    util.FormatUnits(balance, util.EtherDecimals),
+		BlockNumber: &blockNumber,
+	}
+	if !blockRef.IsPending() {
+		response.BlockHash = header.Hash().Hex()
+	}
+	return response, nil
+}
+
+func (s *accountService) GetBalances(ctx context.Context, request serializers.GetBalancesRequest) (*serializers.GetBalancesResponse, *util.ErrorInfo) {
+	blockRef, header, errInfo := resolveBlock(ctx, s.client, s.logger, request.Block)
+	if errInfo != nil {
+		return nil, errInfo
+	}
+
+	var blockArg interface{}
+	switch {
+	case blockRef.Hash != nil:
+		blockArg = rpc.BlockNumberOrHashWithHash(*blockRef.Hash, false)
+	case blockRef.IsPending():
+		blockArg = rpc.PendingBlockNumber
+	default:
+		blockArg = hexutil.EncodeBig(header.Number)
+	}
+
+	results := make([]hexutil.Big, len(request.Addresses))
+	batch := make([]rpc.BatchElem, len(request.Addresses))
+	for i, address := range request.Addresses {
+		batch[i] = rpc.BatchElem{
+			Method: "eth_getBalance",
+			Args:   []interface{}{common.HexToAddress(address), blockArg},
+			Result: &results[i],
+		}
+	}
+	err := s.client.Client().BatchCallContext(ctx, batch)
+	if err != nil {
+		s.logger.Error("GetBalances batch call error", zap.Error(err), zap.Int("size", len(batch)))
+		return nil, &util.ErrorInfo{
+			HttpCode: http.StatusInternalServerError,
+			Message:  util.InternalServiceErrorMessage,
+			Err:      err,
+		}
+	}
+
+	blockNumber := header.Number.Uint64()
+	response := &serializers.GetBalancesResponse{
+		BlockNumber: &blockNumber,
+		Balances:    make([]serializers.GetBalanceResponse, len(request.Addresses)),
+	}
+	if !blockRef.IsPending() {
+		response.BlockHash = header.Hash().Hex()
+	}
+	for i, address := range request.Addresses {
+		item := serializers.GetBalanceResponse{Address: address}
+		if batch[i].Error != nil {
+			item.Error = batch[i].Error.Error()
+		} else {
+			balance := results[i].ToInt()
+			item.Wei = balance.String()
+			item.EthValue = util.FormatUnits(balance, util.EtherDecimals)
+		}
+		response.Balances[i] = item
+	}
+	return response, nil
+}
+
+func (s *accountService) GetTokenBalance(ctx context.Context, request serializers.GetTokenBalanceRequest) (*serializers.GetTokenBalanceResponse, *util.ErrorInfo) {
+	address := common.HexToAddress(request.Address)
+	token := common.HexToAddress(request.Token)
+	metadata, err := s.tokens.Get(ctx, token)
+	if err != nil {
+		return nil, tokenCallError(s.logger, "GetTokenBalance getting token metadata error", token, err)
+	}
+
+	balance, err := callTokenUint(&bind.CallOpts{Context: ctx}, s.client, token, "balanceOf", address)
+	if err != nil {
+		return nil, tokenCallError(s.logger, "GetTokenBalance getting balance error", token, err)
+	}
+	return &serializers.GetTokenBalanceResponse{
 		Address:  request.Address,
+		Token:    request.Token,
+		Symbol:   metadata.Symbol,
+		Decimals: metadata.Decimals,
+		Balance:  balance.String(),
+		Value:    util.FormatUnits(balance, int(metadata.Decimals)),
+	}, nil
+}
+
+func (s *accountService) GetTokenAllowance(ctx context.Context, request serializers.GetTokenAllowanceRequest) (*serializers.GetTokenAllowanceResponse, *util.ErrorInfo) {
+	owner := common.HexToAddress(request.Address)
+	token := common.HexToAddress(request.Token)
+	spender := common.HexToAddress(request.Spender)
+	metadata, err := s.tokens.Get(ctx, token)
+	if err != nil {
+		return nil, tokenCallError(s.logger, "GetTokenAllowance getting token metadata error", token, err)
+	}
+
+	allowance, err := callTokenUint(&bind.CallOpts{Context: ctx}, s.client, token, "allowance", owner, spender)
+	if err != nil {
+		return nil, tokenCallError(s.logger, "GetTokenAllowance getting allowance error", token, err)
+	}
+	return &serializers.GetTokenAllowanceResponse{
+		Address:   request.Address,
+		Token:     request.Token,
+		Spender:   request.Spender,
+		Symbol:    metadata.Symbol,
+		Decimals:  metadata.Decimals,
+		Allowance: allowance.String(),
+		Value:     util.FormatUnits(allowance, int(metadata.Decimals)),
+		Unlimited: allowance.Cmp(math.MaxBig256) == 0,
 	}, nil
 }
 
 func (s *accountService) CreateAccount() (*serializers.CreateAccountResponse, *util.ErrorInfo) {
+	// Accounts of an external signer or a fixed key set are managed by the operator, a key
+	// generated here could never be used by the signer.
+	if s.config.Signer != "keystore" {
+		return nil, &util.ErrorInfo{
+			HttpCode: http.StatusBadRequest,
+			Message:  util.AccountCreationUnsupportedErrorMessage,
+			Err:      errors.New("accounts are managed by the configured signer"),
+		}
+	}
+	if s.config.AllowRawPrivateKey {
+		return s.createRawAccount()
+	}
+	account, err := s.keyStore.NewAccount(s.config.Passphrase)
+	if err != nil {
+		s.logger.Error("CreateAccount keystore new account error", zap.Error(err))
+		return nil, &util.ErrorInfo{
+			HttpCode: http.StatusInternalServerError,
+			Message:  util.InternalServiceErrorMessage,
+			Err:      err,
+		}
+	}
+	return &serializers.CreateAccountResponse{
+		Address: account.Address.Hex(),
+	}, nil
+}
+
+// createRawAccount keeps the legacy behaviour of handing the private key back to the caller.
+// The key is imported into the keystore as well so the account can also be used without it.
+func (s *accountService) createRawAccount() (*serializers.CreateAccountResponse, *util.ErrorInfo) {
 	privateKeyECDSA, err := crypto.GenerateKey()
 	if err != nil {
 		s.logger.Error("CreateAccount generateKey error", zap.Error(err))
@@ -65,19 +226,17 @@ func (s *accountService) CreateAccount() (*serializers.CreateAccountResponse, *u
 	privateKeyBytes := crypto.FromECDSA(privateKeyECDSA)
 	privateKeyStr := hexutil.Encode(privateKeyBytes)
 
-	publicKey := privateKeyECDSA.Public()
-	publicKeyECDSA, ok := publicKey.(*ecdsa.PublicKey)
-	if !ok {
-		s.logger.Error("CreateAccount publicKey convert to ECDSA error", zap.Error(err))
+	account, err := s.keyStore.ImportECDSA(privateKeyECDSA, s.config.Passphrase)
+	if err != nil {
+		s.logger.Error("CreateAccount keystore import error", zap.Error(err))
 		return nil, &util.ErrorInfo{
 			HttpCode: http.StatusInternalServerError,
 			Message:  util.InternalServiceErrorMessage,
-			Err:      errors.New("public key convert error"),
+			Err:      err,
 		}
 	}
-	address := crypto.PubkeyToAddress(*publicKeyECDSA).Hex()
 	return &serializers.CreateAccountResponse{
-		Address:    address,
+		Address:    account.Address.Hex(),
 		PrivateKey: privateKeyStr,
 	}, nil
 }
@@ -0,0 +1,51 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"go.uber.org/zap"
+	"golang-ethereum-example-api/pkg/logging"
+	"golang-ethereum-example-api/pkg/util"
+	"net/http"
+)
+
+// resolveBlockHeader pins a block reference to a concrete header so that reads made with it
+// refer to a single, reportable block.
+func resolveBlockHeader(ctx context.Context, client *ethclient.Client, ref *util.BlockReference) (*types.Header, error) {
+	if ref.Hash != nil {
+		return client.HeaderByHash(ctx, *ref.Hash)
+	}
+	return client.HeaderByNumber(ctx, ref.Number)
+}
+
+// resolveBlock parses the requested block and pins it to a header that is reported back.
+func resolveBlock(ctx context.Context, client *ethclient.Client, logger *logging.LogWrapper, block string) (*util.BlockReference, *types.Header, *util.ErrorInfo) {
+	blockRef, err := util.ParseBlockReference(block)
+	if err != nil {
+		return nil, nil, &util.ErrorInfo{
+			HttpCode: http.StatusBadRequest,
+			Message:  util.InvalidBlockErrorMessage,
+			Err:      err,
+		}
+	}
+	header, err := resolveBlockHeader(ctx, client, blockRef)
+	if errors.Is(err, ethereum.NotFound) {
+		return nil, nil, &util.ErrorInfo{
+			HttpCode: http.StatusNotFound,
+			Message:  util.BlockNotFoundErrorMessage,
+			Err:      err,
+		}
+	}
+	if err != nil {
+		logger.Error("Getting block header error", zap.Error(err), zap.String("block", block))
+		return nil, nil, &util.ErrorInfo{
+			HttpCode: http.StatusInternalServerError,
+			Message:  util.InternalServiceErrorMessage,
+			Err:      err,
+		}
+	}
+	return blockRef, header, nil
+}
@@ -0,0 +1,84 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/core/types"
+	"go.uber.org/zap"
+	"golang-ethereum-example-api/pkg/util"
+	"golang-ethereum-example-api/serializers"
+	"net/http"
+	"time"
+)
+
+const (
+	receiptPollInterval = time.Second
+	// writeTimeoutMargin leaves the handler enough time to write the response before the
+	// server closes the connection.
+	writeTimeoutMargin = 2 * time.Second
+)
+
+// awaitReceipt waits for the requested confirmations, if any. A nil receipt summary means the
+// caller did not ask to wait or the wait timed out.
+func (s *transferService) awaitReceipt(ctx context.Context, tx *types.Transaction, confirmations uint64, timeoutSeconds uint64) (*serializers.GetTransactionResponse, *util.ErrorInfo) {
+	if confirmations == 0 {
+		return nil, nil
+	}
+	receipt, err := s.waitForConfirmations(ctx, tx, confirmations, timeoutSeconds)
+	if err != nil {
+		s.logger.Error("Waiting for confirmations error", zap.Error(err), zap.String("hash", tx.Hash().Hex()))
+		return nil, &util.ErrorInfo{
+			HttpCode: http.StatusInternalServerError,
+			Message:  util.InternalServiceErrorMessage,
+			Err:      err,
+		}
+	}
+	return receipt, nil
+}
+
+// confirmationTimeout bounds the caller supplied timeout by the server write timeout.
+func (s *transferService) confirmationTimeout(timeoutSeconds uint64) time.Duration {
+	timeout := s.serverConfig.WriteTimeout - writeTimeoutMargin
+	if timeout <= 0 {
+		timeout = s.serverConfig.WriteTimeout
+	}
+	requested := time.Duration(timeoutSeconds) * time.Second
+	if requested > 0 && requested < timeout {
+		timeout = requested
+	}
+	return timeout
+}
+
+// waitForConfirmations polls the receipt of tx until it is buried under the requested number
+// of confirmations. A nil receipt summary without an error means the timeout expired or the
+// request was cancelled first.
+func (s *transferService) waitForConfirmations(ctx context.Context, tx *types.Transaction, confirmations uint64, timeoutSeconds uint64) (*serializers.GetTransactionResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.confirmationTimeout(timeoutSeconds))
+	defer cancel()
+
+	ticker := time.NewTicker(receiptPollInterval)
+	defer ticker.Stop()
+	for {
+		receipt, err := s.client.TransactionReceipt(ctx, tx.Hash())
+		if err == nil {
+			latestBlock, err := s.client.BlockNumber(ctx)
+			if err != nil && ctx.Err() == nil {
+				return nil, err
+			}
+			if err == nil && latestBlock+1 >= receipt.BlockNumber.Uint64()+confirmations {
+				response := s.transactionSummary(tx)
+				fillReceipt(response, receipt, tx, latestBlock)
+				return response, nil
+			}
+		} else if !errors.Is(err, ethereum.NotFound) && ctx.Err() == nil {
+			return nil, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, nil
+		case <-ticker.C:
+		}
+	}
+}
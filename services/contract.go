@@ -0,0 +1,412 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"go.uber.org/zap"
+	"golang-ethereum-example-api/pkg/abijson"
+	"golang-ethereum-example-api/pkg/logging"
+	"golang-ethereum-example-api/pkg/settings"
+	"golang-ethereum-example-api/pkg/signer"
+	"golang-ethereum-example-api/pkg/util"
+	"golang-ethereum-example-api/serializers"
+	"math/big"
+	"net/http"
+	"sort"
+)
+
+type ContractService interface {
+	RegisterContract(ctx context.Context, request serializers.RegisterContractRequest) (*serializers.ContractResponse, *util.ErrorInfo)
+	GetContract(ctx context.Context, request serializers.GetContractRequest) (*serializers.ContractResponse, *util.ErrorInfo)
+	CallContract(ctx context.Context, request serializers.CallContractRequest) (*serializers.CallContractResponse, *util.ErrorInfo)
+	TransactContract(ctx context.Context, request serializers.TransactContractRequest) (*serializers.TransactContractResponse, *util.ErrorInfo)
+	DeployContract(ctx context.Context, request serializers.DeployContractRequest) (*serializers.DeployContractResponse, *util.ErrorInfo)
+}
+
+// contractService sends through the same pipeline as transferService so contract transactions
+// share its signer, nonce and fee handling.
+type contractService struct {
+	*transferService
+	registry ContractRegistry
+}
+
+func NewContractService(client *ethclient.Client, config *settings.EthereumClient, serverConfig *settings.Server, walletConfig *settings.Wallet, signers signer.Provider, nonces NonceManager, ledger TransferLedger, registry ContractRegistry, chainID *big.Int, logger *logging.LogWrapper) ContractService {
+	return &contractService{
+		transferService: &transferService{
+			client:       client,
+			config:       config,
+			serverConfig: serverConfig,
+			walletConfig: walletConfig,
+			signers:      signers,
+			nonces:       nonces,
+			ledger:       ledger,
+			chainID:      chainID,
+			logger:       logger,
+		},
+		registry: registry,
+	}
+}
+
+func (s *contractService) RegisterContract(ctx context.Context, request serializers.RegisterContractRequest) (*serializers.ContractResponse, *util.ErrorInfo) {
+	contract, err := s.registry.Register(request.Name, common.HexToAddress(request.Address), request.ABI)
+	if errors.Is(err, ErrInvalidABI) {
+		return nil, &util.ErrorInfo{
+			HttpCode: http.StatusBadRequest,
+			Message:  util.InvalidAbiErrorMessage,
+			Detail:   err.Error(),
+			Err:      err,
+		}
+	}
+	if err != nil {
+		s.logger.Error("RegisterContract saving contract error", zap.Error(err), zap.String("name", request.Name))
+		return nil, &util.ErrorInfo{
+			HttpCode: http.StatusInternalServerError,
+			Message:  util.InternalServiceErrorMessage,
+			Err:      err,
+		}
+	}
+	return contractResponse(contract), nil
+}
+
+func (s *contractService) GetContract(ctx context.Context, request serializers.GetContractRequest) (*serializers.ContractResponse, *util.ErrorInfo) {
+	contract, errInfo := s.lookupContract(request.Name)
+	if errInfo != nil {
+		return nil, errInfo
+	}
+	return contractResponse(contract), nil
+}
+
+func (s *contractService) CallContract(ctx context.Context, request serializers.CallContractRequest) (*serializers.CallContractResponse, *util.ErrorInfo) {
+	contract, method, data, errInfo := s.packContractCall(request.Name, request.Method, request.Args)
+	if errInfo != nil {
+		return nil, errInfo
+	}
+	blockRef, header, errInfo := resolveBlock(ctx, s.client, s.logger, request.Block)
+	if errInfo != nil {
+		return nil, errInfo
+	}
+
+	msg := ethereum.CallMsg{
+		From:  common.HexToAddress(request.From),
+		To:    &contract.Address,
+		Value: parseWeiValue(request.Value),
+		Data:  data,
+	}
+	var output []byte
+	var err error
+	switch {
+	case blockRef.Hash != nil:
+		output, err = s.client.CallContractAtHash(ctx, msg, *blockRef.Hash)
+	case blockRef.IsPending():
+		output, err = s.client.PendingCallContract(ctx, msg)
+	default:
+		output, err = s.client.CallContract(ctx, msg, header.Number)
+	}
+	if err != nil {
+		return nil, s.contractCallError("CallContract call error", contract, err)
+	}
+
+	values, err := method.Outputs.Unpack(output)
+	if err != nil {
+		return nil, &util.ErrorInfo{
+			HttpCode: http.StatusBadRequest,
+			Message:  util.InvalidAbiErrorMessage,
+			Detail:   fmt.Sprintf("cannot decode %s output: %v", method.Sig, err),
+			Err:      err,
+		}
+	}
+
+	blockNumber := header.Number.Uint64()
+	response := &serializers.CallContractResponse{
+		Contract:    contract.Name,
+		Address:     contract.Address.Hex(),
+		Method:      method.Sig,
+		BlockNumber: &blockNumber,
+		Outputs:     abijson.FormatValues(method.Outputs, values),
+	}
+	if !blockRef.IsPending() {
+		response.BlockHash = header.Hash().Hex()
+	}
+	return response, nil
+}
+
+func (s *contractService) TransactContract(ctx context.Context, request serializers.TransactContractRequest) (*serializers.TransactContractResponse, *util.ErrorInfo) {
+	contract, method, data, errInfo := s.packContractCall(request.Name, request.Method, request.Args)
+	if errInfo != nil {
+		return nil, errInfo
+	}
+	if method.IsConstant() {
+		return nil, &util.ErrorInfo{
+			HttpCode: http.StatusBadRequest,
+			Message:  util.ReadOnlyMethodErrorMessage,
+			Detail:   fmt.Sprintf("%s does not modify state, use the call endpoint", method.Sig),
+			Err:      errors.New("read only method"),
+		}
+	}
+	value := parseWeiValue(request.Value)
+	if value.Sign() > 0 && !method.IsPayable() {
+		return nil, &util.ErrorInfo{
+			HttpCode: http.StatusBadRequest,
+			Message:  util.MethodNotPayableErrorMessage,
+			Detail:   fmt.Sprintf("%s does not accept value", method.Sig),
+			Err:      errors.New("method not payable"),
+		}
+	}
+
+	fromAccount := common.HexToAddress(request.FromAddress)
+	_, err := s.client.PendingCallContract(ctx, ethereum.CallMsg{From: fromAccount, To: &contract.Address, Value: value, Data: data})
+	if err != nil {
+		return nil, s.contractCallError("TransactContract simulating call error", contract, err)
+	}
+
+	signedTx, fees, errInfo := s.sendTransaction(ctx, txRequest{
+		kind:                 transferKindContractTransact,
+		request:              request,
+		from:                 fromAccount,
+		privateKey:           request.PrivateKey,
+		to:                   &contract.Address,
+		value:                value,
+		data:                 data,
+		maxFeePerGas:         request.MaxFeePerGas,
+		maxPriorityFeePerGas: request.MaxPriorityFeePerGas,
+		gasLimit:             request.GasLimit,
+	})
+	if errInfo != nil {
+		return nil, errInfo
+	}
+	response := &serializers.TransactContractResponse{
+		Contract:        contract.Name,
+		Address:         contract.Address.Hex(),
+		Method:          method.Sig,
+		TransactionHash: signedTx.Hash().Hex(),
+		Value:           value.String(),
+		GasLimit:        signedTx.Gas(),
+		TransactionFees: fees.serialize(),
+	}
+	response.Receipt, errInfo = s.awaitReceipt(ctx, signedTx, request.WaitConfirmations, request.TimeoutSeconds)
+	if errInfo != nil {
+		return nil, errInfo
+	}
+	return response, nil
+}
+
+// DeployContract sends a contract creation transaction. The contract address is predicted from
+// the sender and nonce, and the contract is registered under the requested name once mined.
+func (s *contractService) DeployContract(ctx context.Context, request serializers.DeployContractRequest) (*serializers.DeployContractResponse, *util.ErrorInfo) {
+	contractABI, err := abi.JSON(bytes.NewReader(request.ABI))
+	if err != nil {
+		return nil, &util.ErrorInfo{
+			HttpCode: http.StatusBadRequest,
+			Message:  util.InvalidAbiErrorMessage,
+			Detail:   err.Error(),
+			Err:      err,
+		}
+	}
+	value := parseWeiValue(request.Value)
+	if value.Sign() > 0 && !contractABI.Constructor.IsPayable() {
+		return nil, &util.ErrorInfo{
+			HttpCode: http.StatusBadRequest,
+			Message:  util.MethodNotPayableErrorMessage,
+			Detail:   "constructor does not accept value",
+			Err:      errors.New("constructor not payable"),
+		}
+	}
+	args, err := abijson.ParseArguments(contractABI.Constructor.Inputs, request.Args)
+	if err != nil {
+		return nil, &util.ErrorInfo{
+			HttpCode: http.StatusBadRequest,
+			Message:  util.InvalidArgumentsErrorMessage,
+			Detail:   err.Error(),
+			Err:      err,
+		}
+	}
+	constructorArgs, err := contractABI.Pack("", args...)
+	if err != nil {
+		return nil, &util.ErrorInfo{
+			HttpCode: http.StatusBadRequest,
+			Message:  util.InvalidArgumentsErrorMessage,
+			Detail:   err.Error(),
+			Err:      err,
+		}
+	}
+	bytecode, _ := hexutil.Decode(request.Bytecode)
+
+	fromAccount := common.HexToAddress(request.FromAddress)
+	signedTx, fees, errInfo := s.sendTransaction(ctx, txRequest{
+		kind:                 transferKindContractDeploy,
+		request:              request,
+		from:                 fromAccount,
+		privateKey:           request.PrivateKey,
+		value:                value,
+		data:                 append(bytecode, constructorArgs...),
+		maxFeePerGas:         request.MaxFeePerGas,
+		maxPriorityFeePerGas: request.MaxPriorityFeePerGas,
+		gasLimit:             request.GasLimit,
+	})
+	if errInfo != nil {
+		return nil, errInfo
+	}
+	response := &serializers.DeployContractResponse{
+		Name:            request.Name,
+		TransactionHash: signedTx.Hash().Hex(),
+		ContractAddress: crypto.CreateAddress(fromAccount, signedTx.Nonce()).Hex(),
+		Value:           value.String(),
+		GasLimit:        signedTx.Gas(),
+		TransactionFees: fees.serialize(),
+	}
+	response.Receipt, errInfo = s.awaitReceipt(ctx, signedTx, request.WaitConfirmations, request.TimeoutSeconds)
+	if errInfo != nil {
+		return nil, errInfo
+	}
+	if response.Receipt == nil || response.Receipt.ContractAddress == "" {
+		return response, nil
+	}
+
+	response.Deployed = true
+	response.ContractAddress = response.Receipt.ContractAddress
+	// The transaction is already mined, so a registry failure is logged rather than returned.
+	_, err = s.registry.Register(request.Name, common.HexToAddress(response.ContractAddress), request.ABI)
+	if err != nil {
+		s.logger.Error("DeployContract registering contract error", zap.Error(err), zap.String("name", request.Name))
+		return response, nil
+	}
+	response.Registered = true
+	return response, nil
+}
+
+func (s *contractService) lookupContract(name string) (*RegisteredContract, *util.ErrorInfo) {
+	contract, ok := s.registry.Get(name)
+	if !ok {
+		return nil, &util.ErrorInfo{
+			HttpCode: http.StatusNotFound,
+			Message:  util.ContractNotFoundErrorMessage,
+			Err:      fmt.Errorf("contract %s is not registered", name),
+		}
+	}
+	return contract, nil
+}
+
+// packContractCall resolves the method by name, or by signature for overloaded methods, and
+// ABI encodes the JSON arguments for it.
+func (s *contractService) packContractCall(name string, methodName string, args []json.RawMessage) (*RegisteredContract, *abi.Method, []byte, *util.ErrorInfo) {
+	contract, errInfo := s.lookupContract(name)
+	if errInfo != nil {
+		return nil, nil, nil, errInfo
+	}
+	method, ok := findMethod(contract.ABI, methodName)
+	if !ok {
+		return nil, nil, nil, &util.ErrorInfo{
+			HttpCode: http.StatusNotFound,
+			Message:  util.MethodNotFoundErrorMessage,
+			Err:      fmt.Errorf("method %s not found in %s", methodName, contract.Name),
+		}
+	}
+
+	values, err := abijson.ParseArguments(method.Inputs, args)
+	if err != nil {
+		return nil, nil, nil, &util.ErrorInfo{
+			HttpCode: http.StatusBadRequest,
+			Message:  util.InvalidArgumentsErrorMessage,
+			Detail:   err.Error(),
+			Err:      err,
+		}
+	}
+	data, err := contract.ABI.Pack(method.Name, values...)
+	if err != nil {
+		return nil, nil, nil, &util.ErrorInfo{
+			HttpCode: http.StatusBadRequest,
+			Message:  util.InvalidArgumentsErrorMessage,
+			Detail:   err.Error(),
+			Err:      err,
+		}
+	}
+	return contract, method, data, nil
+}
+
+func findMethod(contractABI abi.ABI, name string) (*abi.Method, bool) {
+	method, ok := contractABI.Methods[name]
+	if ok {
+		return &method, true
+	}
+	for _, method := range contractABI.Methods {
+		if method.Sig == name {
+			return &method, true
+		}
+	}
+	return nil, false
+}
+
+func (s *contractService) contractCallError(msg string, contract *RegisteredContract, err error) *util.ErrorInfo {
+	var rpcErr rpc.Error
+	if errors.As(err, &rpcErr) {
+		return &util.ErrorInfo{
+			HttpCode: http.StatusBadRequest,
+			Message:  util.ExecutionRevertedErrorMessage,
+			Detail:   contractRevertReason(contract.ABI, err),
+			Err:      err,
+		}
+	}
+	s.logger.Error(msg, zap.Error(err), zap.String("contract", contract.Name))
+	return &util.ErrorInfo{
+		HttpCode: http.StatusInternalServerError,
+		Message:  util.InternalServiceErrorMessage,
+		Err:      err,
+	}
+}
+
+// contractRevertReason decodes custom errors declared in the contract's ABI, e.g.
+// InsufficientBalance({"available":"0","required":"5"}), before falling back to revertReason.
+func contractRevertReason(contractABI abi.ABI, err error) string {
+	data, ok := revertData(err)
+	if ok && len(data) >= 4 {
+		for _, abiErr := range contractABI.Errors {
+			if !bytes.Equal(abiErr.ID[:4], data[:4]) {
+				continue
+			}
+			values, unpackErr := abiErr.Inputs.Unpack(data[4:])
+			if unpackErr != nil {
+				break
+			}
+			encoded, _ := json.Marshal(abijson.FormatValues(abiErr.Inputs, values))
+			return fmt.Sprintf("%s(%s)", abiErr.Name, encoded)
+		}
+	}
+	return revertReason(err)
+}
+
+func contractResponse(contract *RegisteredContract) *serializers.ContractResponse {
+	response := &serializers.ContractResponse{
+		Name:    contract.Name,
+		Address: contract.Address.Hex(),
+		Methods: make([]string, 0, len(contract.ABI.Methods)),
+		Events:  make([]string, 0, len(contract.ABI.Events)),
+		ABI:     contract.RawABI,
+	}
+	for _, method := range contract.ABI.Methods {
+		response.Methods = append(response.Methods, method.Sig)
+	}
+	for _, event := range contract.ABI.Events {
+		response.Events = append(response.Events, event.Sig)
+	}
+	sort.Strings(response.Methods)
+	sort.Strings(response.Events)
+	return response
+}
+
+func parseWeiValue(value string) *big.Int {
+	if value == "" {
+		return new(big.Int)
+	}
+	n, _ := new(big.Int).SetString(value, 10)
+	return n
+}
@@ -0,0 +1,139 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+var ErrInvalidABI = errors.New("invalid ABI")
+
+type RegisteredContract struct {
+	Name    string
+	Address common.Address
+	ABI     abi.ABI
+	RawABI  json.RawMessage
+}
+
+// ContractRegistry keeps the contracts uploaded through the API. Every contract is stored as
+// <name>.json in the registry directory so registrations survive a restart.
+type ContractRegistry interface {
+	Register(name string, address common.Address, rawABI json.RawMessage) (*RegisteredContract, error)
+	Get(name string) (*RegisteredContract, bool)
+	GetByAddress(address common.Address) (*RegisteredContract, bool)
+}
+
+type contractRegistry struct {
+	dir       string
+	mu        sync.RWMutex
+	contracts map[string]*RegisteredContract
+	addresses map[common.Address]*RegisteredContract
+}
+
+type contractFile struct {
+	Name    string          `json:"name"`
+	Address string          `json:"address"`
+	ABI     json.RawMessage `json:"abi"`
+}
+
+func NewContractRegistry(dir string) (ContractRegistry, error) {
+	r := &contractRegistry{
+		dir:       dir,
+		contracts: make(map[string]*RegisteredContract),
+		addresses: make(map[common.Address]*RegisteredContract),
+	}
+	err := os.MkdirAll(dir, 0700)
+	if err != nil {
+		return nil, err
+	}
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		var file contractFile
+		err = json.Unmarshal(data, &file)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		contract, err := newRegisteredContract(file.Name, common.HexToAddress(file.Address), file.ABI)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		r.add(contract)
+	}
+	return r, nil
+}
+
+func newRegisteredContract(name string, address common.Address, rawABI json.RawMessage) (*RegisteredContract, error) {
+	parsed, err := abi.JSON(bytes.NewReader(rawABI))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidABI, err)
+	}
+	return &RegisteredContract{Name: name, Address: address, ABI: parsed, RawABI: rawABI}, nil
+}
+
+// Register adds the contract or replaces an existing one with the same name.
+func (r *contractRegistry) Register(name string, address common.Address, rawABI json.RawMessage) (*RegisteredContract, error) {
+	contract, err := newRegisteredContract(name, address, rawABI)
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.MarshalIndent(contractFile{Name: name, Address: address.Hex(), ABI: rawABI}, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	path := filepath.Join(r.dir, strings.ToLower(name)+".json")
+	tmpPath := path + ".tmp"
+	err = os.WriteFile(tmpPath, data, 0600)
+	if err != nil {
+		return nil, err
+	}
+	err = os.Rename(tmpPath, path)
+	if err != nil {
+		return nil, err
+	}
+	r.add(contract)
+	return contract, nil
+}
+
+// add indexes the contract by name and address. When several names share an address, the
+// latest registration is used to decode its logs.
+func (r *contractRegistry) add(contract *RegisteredContract) {
+	previous, ok := r.contracts[strings.ToLower(contract.Name)]
+	if ok && r.addresses[previous.Address] == previous {
+		delete(r.addresses, previous.Address)
+	}
+	r.contracts[strings.ToLower(contract.Name)] = contract
+	r.addresses[contract.Address] = contract
+}
+
+func (r *contractRegistry) Get(name string) (*RegisteredContract, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	contract, ok := r.contracts[strings.ToLower(name)]
+	return contract, ok
+}
+
+func (r *contractRegistry) GetByAddress(address common.Address) (*RegisteredContract, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	contract, ok := r.addresses[address]
+	return contract, ok
+}
@@ -0,0 +1,141 @@
+package services
+
+import (
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"golang-ethereum-example-api/pkg/abijson"
+	"golang-ethereum-example-api/pkg/contracts"
+	"golang-ethereum-example-api/serializers"
+	"slices"
+)
+
+type namedABI struct {
+	name string
+	abi  abi.ABI
+}
+
+// standardEventABIs are tried, in order, for logs of contracts that are not registered. ERC-20
+// and ERC-721 share the Transfer signature and are told apart by the number of indexed topics.
+var standardEventABIs = []namedABI{
+	{name: "ERC20", abi: contracts.ERC20},
+	{name: "ERC721", abi: contracts.ERC721},
+	{name: "ERC1155", abi: contracts.ERC1155},
+}
+
+var hashType, _ = abi.NewType("bytes32", "", nil)
+
+type eventDecoder struct {
+	registry ContractRegistry
+}
+
+func newEventDecoder(registry ContractRegistry) *eventDecoder {
+	return &eventDecoder{registry: registry}
+}
+
+func (d *eventDecoder) logResponse(log types.Log) serializers.LogResponse {
+	response := serializers.LogResponse{
+		Address:          log.Address.Hex(),
+		Topics:           make([]string, len(log.Topics)),
+		Data:             hexutil.Encode(log.Data),
+		BlockNumber:      log.BlockNumber,
+		BlockHash:        log.BlockHash.Hex(),
+		TransactionHash:  log.TxHash.Hex(),
+		TransactionIndex: log.TxIndex,
+		LogIndex:         log.Index,
+		Removed:          log.Removed,
+		Event:            d.decode(log),
+	}
+	for i, topic := range log.Topics {
+		response.Topics[i] = topic.Hex()
+	}
+	return response
+}
+
+// matchLog applies query to log the way the node does for eth_getLogs, ignoring the block range.
+func matchLog(query ethereum.FilterQuery, log types.Log) bool {
+	if len(query.Addresses) > 0 && !slices.Contains(query.Addresses, log.Address) {
+		return false
+	}
+	if len(query.Topics) > len(log.Topics) {
+		return false
+	}
+	for i, alternatives := range query.Topics {
+		if len(alternatives) > 0 && !slices.Contains(alternatives, log.Topics[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// decode returns the event name and arguments of log, or nil when no known ABI matches it.
+func (d *eventDecoder) decode(log types.Log) *serializers.DecodedEventResponse {
+	if len(log.Topics) == 0 {
+		return nil
+	}
+	candidates := standardEventABIs
+	contract, ok := d.registry.GetByAddress(log.Address)
+	if ok {
+		candidates = append([]namedABI{{name: contract.Name, abi: contract.ABI}}, candidates...)
+	}
+	for _, candidate := range candidates {
+		event, err := candidate.abi.EventByID(log.Topics[0])
+		if err != nil {
+			continue
+		}
+		args, ok := decodeEventArgs(event, log)
+		if ok {
+			return &serializers.DecodedEventResponse{
+				Contract:  candidate.name,
+				Name:      event.Name,
+				Signature: event.Sig,
+				Args:      args,
+			}
+		}
+	}
+	return nil
+}
+
+func decodeEventArgs(event *abi.Event, log types.Log) (interface{}, bool) {
+	var indexed abi.Arguments
+	for _, input := range event.Inputs {
+		if input.Indexed {
+			indexed = append(indexed, input)
+		}
+	}
+	if len(indexed) != len(log.Topics)-1 {
+		return nil, false
+	}
+
+	values := make(map[string]interface{}, len(event.Inputs))
+	err := abi.ParseTopicsIntoMap(values, indexed, log.Topics[1:])
+	if err != nil {
+		return nil, false
+	}
+	err = event.Inputs.UnpackIntoMap(values, log.Data)
+	if err != nil {
+		return nil, false
+	}
+
+	// Indexed strings, bytes, arrays and tuples are only available as their keccak hash.
+	arguments := make(abi.Arguments, len(event.Inputs))
+	ordered := make([]interface{}, len(event.Inputs))
+	for i, input := range event.Inputs {
+		arguments[i] = input
+		if input.Indexed && !isTopicValueType(input.Type) {
+			arguments[i].Type = hashType
+		}
+		ordered[i] = values[input.Name]
+	}
+	return abijson.FormatValues(arguments, ordered), true
+}
+
+func isTopicValueType(t abi.Type) bool {
+	switch t.T {
+	case abi.StringTy, abi.BytesTy, abi.SliceTy, abi.ArrayTy, abi.TupleTy:
+		return false
+	default:
+		return true
+	}
+}
@@ -0,0 +1,297 @@
+package services
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"go.etcd.io/bbolt"
+	"math"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	// TransferStatusSubmitting is recorded before the transaction is handed to the node.
+	TransferStatusSubmitting = "submitting"
+	TransferStatusPending    = "pending"
+	// TransferStatusRejected is used for transactions the node refused to accept.
+	TransferStatusRejected = "rejected"
+	// TransferStatusMined is used for transactions in a block that is not yet buried under
+	// the confirmation depth, they become confirmed or failed once it is.
+	TransferStatusMined     = "mined"
+	TransferStatusConfirmed = "confirmed"
+	TransferStatusFailed    = "failed"
+	TransferStatusDropped   = "dropped"
+	TransferStatusReplaced  = "replaced"
+
+	ledgerFile = "ledger.db"
+)
+
+var (
+	transfersBucket         = []byte("transfers")
+	transferAddressesBucket = []byte("addresses")
+	pendingTransfersBucket  = []byte("pending")
+)
+
+var ErrTransferNotFound = errors.New("transfer not found")
+
+type TransferStatusChange struct {
+	Status string    `json:"status"`
+	Detail string    `json:"detail,omitempty"`
+	At     time.Time `json:"at"`
+}
+
+// TransferRecord is a single broadcast attempt. Request holds the API request that caused it
+// with the private key removed.
+type TransferRecord struct {
+	Id             uint64                 `json:"id"`
+	Kind           string                 `json:"kind"`
+	From           common.Address         `json:"from"`
+	To             *common.Address        `json:"to,omitempty"`
+	Value          string                 `json:"value"`
+	Nonce          uint64                 `json:"nonce"`
+	Hash           common.Hash            `json:"hash"`
+	RawTransaction hexutil.Bytes          `json:"rawTransaction"`
+	Request        json.RawMessage        `json:"request,omitempty"`
+	Status         string                 `json:"status"`
+	BlockNumber    *uint64                `json:"blockNumber,omitempty"`
+	History        []TransferStatusChange `json:"history"`
+	CreatedAt      time.Time              `json:"createdAt"`
+	UpdatedAt      time.Time              `json:"updatedAt"`
+}
+
+// TransferQuery lists records newest first. Address matches the sender or the recipient and
+// Cursor is the id of the last record of the previous page.
+type TransferQuery struct {
+	Address *common.Address
+	Status  string
+	Cursor  uint64
+	Limit   int
+}
+
+// TransferLedger is the repository of submitted transactions.
+type TransferLedger interface {
+	Record(record *TransferRecord, detail string) error
+	UpdateStatus(id uint64, status string, detail string, blockNumber *uint64) error
+	// ResolveSubmission moves a submitting record to status. A record the tracker has already
+	// moved on is left unchanged.
+	ResolveSubmission(id uint64, status string, detail string) error
+	List(query TransferQuery) ([]*TransferRecord, uint64, error)
+	Pending() ([]*TransferRecord, error)
+	Close() error
+}
+
+type boltTransferLedger struct {
+	db *bbolt.DB
+}
+
+// NewTransferLedger opens the ledger stored in <dir>/ledger.db, creating it when missing.
+func NewTransferLedger(dir string) (TransferLedger, error) {
+	err := os.MkdirAll(dir, 0700)
+	if err != nil {
+		return nil, err
+	}
+	db, err := bbolt.Open(filepath.Join(dir, ledgerFile), 0600, &bbolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, err
+	}
+	err = db.Update(func(tx *bbolt.Tx) error {
+		for _, name := range [][]byte{transfersBucket, transferAddressesBucket, pendingTransfersBucket} {
+			_, err := tx.CreateBucketIfNotExists(name)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+	return &boltTransferLedger{db: db}, nil
+}
+
+// Record stores a new attempt and assigns its Id. detail explains the initial status.
+func (l *boltTransferLedger) Record(record *TransferRecord, detail string) error {
+	now := time.Now().UTC()
+	record.CreatedAt = now
+	record.UpdatedAt = now
+	record.History = []TransferStatusChange{{Status: record.Status, Detail: detail, At: now}}
+	return l.db.Update(func(tx *bbolt.Tx) error {
+		transfers := tx.Bucket(transfersBucket)
+		id, err := transfers.NextSequence()
+		if err != nil {
+			return err
+		}
+		record.Id = id
+		err = putTransfer(transfers, record)
+		if err != nil {
+			return err
+		}
+		addresses := tx.Bucket(transferAddressesBucket)
+		err = addresses.Put(addressKey(record.From, id), nil)
+		if err != nil {
+			return err
+		}
+		if record.To != nil {
+			err = addresses.Put(addressKey(*record.To, id), nil)
+			if err != nil {
+				return err
+			}
+		}
+		if isTrackedStatus(record.Status) {
+			return tx.Bucket(pendingTransfersBucket).Put(idKey(id), nil)
+		}
+		return nil
+	})
+}
+
+func (l *boltTransferLedger) UpdateStatus(id uint64, status string, detail string, blockNumber *uint64) error {
+	return l.db.Update(func(tx *bbolt.Tx) error {
+		return updateStatus(tx, id, "", status, detail, blockNumber)
+	})
+}
+
+func (l *boltTransferLedger) ResolveSubmission(id uint64, status string, detail string) error {
+	return l.db.Update(func(tx *bbolt.Tx) error {
+		return updateStatus(tx, id, TransferStatusSubmitting, status, detail, nil)
+	})
+}
+
+// updateStatus changes the status of a record, only if it currently is from when from is set.
+func updateStatus(tx *bbolt.Tx, id uint64, from string, status string, detail string, blockNumber *uint64) error {
+	transfers := tx.Bucket(transfersBucket)
+	record, err := getTransfer(transfers, id)
+	if err != nil {
+		return err
+	}
+	if from != "" && record.Status != from {
+		return nil
+	}
+	now := time.Now().UTC()
+	record.Status = status
+	record.BlockNumber = blockNumber
+	record.UpdatedAt = now
+	record.History = append(record.History, TransferStatusChange{Status: status, Detail: detail, At: now})
+	err = putTransfer(transfers, record)
+	if err != nil {
+		return err
+	}
+	if isTrackedStatus(status) {
+		return tx.Bucket(pendingTransfersBucket).Put(idKey(id), nil)
+	}
+	return tx.Bucket(pendingTransfersBucket).Delete(idKey(id))
+}
+
+// isTrackedStatus reports whether a record with status may still change, so the tracker has
+// to keep checking it.
+func isTrackedStatus(status string) bool {
+	return status == TransferStatusSubmitting || status == TransferStatusPending || status == TransferStatusMined
+}
+
+// List returns up to query.Limit records and the cursor of the next page, which is zero on
+// the last page.
+func (l *boltTransferLedger) List(query TransferQuery) ([]*TransferRecord, uint64, error) {
+	cursor := query.Cursor
+	if cursor == 0 {
+		cursor = math.MaxUint64
+	}
+	var prefix []byte
+	bucket := transfersBucket
+	if query.Address != nil {
+		prefix = query.Address.Bytes()
+		bucket = transferAddressesBucket
+	}
+
+	records := make([]*TransferRecord, 0, query.Limit)
+	var next uint64
+	err := l.db.View(func(tx *bbolt.Tx) error {
+		transfers := tx.Bucket(transfersBucket)
+		c := tx.Bucket(bucket).Cursor()
+		// Walk the keys below prefix+cursor backwards, so the newest records come first.
+		k, _ := c.Seek(append(bytes.Clone(prefix), idKey(cursor)...))
+		if k == nil {
+			k, _ = c.Last()
+		} else {
+			k, _ = c.Prev()
+		}
+		for ; k != nil && bytes.HasPrefix(k, prefix); k, _ = c.Prev() {
+			id := binary.BigEndian.Uint64(k[len(prefix):])
+			record, err := getTransfer(transfers, id)
+			if err != nil {
+				return err
+			}
+			if query.Status != "" && record.Status != query.Status {
+				continue
+			}
+			if len(records) == query.Limit {
+				next = records[len(records)-1].Id
+				return nil
+			}
+			records = append(records, record)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	return records, next, nil
+}
+
+// Pending returns the records whose status is not final yet.
+func (l *boltTransferLedger) Pending() ([]*TransferRecord, error) {
+	var records []*TransferRecord
+	err := l.db.View(func(tx *bbolt.Tx) error {
+		transfers := tx.Bucket(transfersBucket)
+		return tx.Bucket(pendingTransfersBucket).ForEach(func(k, _ []byte) error {
+			record, err := getTransfer(transfers, binary.BigEndian.Uint64(k))
+			if err != nil {
+				return err
+			}
+			records = append(records, record)
+			return nil
+		})
+	})
+	return records, err
+}
+
+func (l *boltTransferLedger) Close() error {
+	return l.db.Close()
+}
+
+func getTransfer(transfers *bbolt.Bucket, id uint64) (*TransferRecord, error) {
+	data := transfers.Get(idKey(id))
+	if data == nil {
+		return nil, fmt.Errorf("%w: %d", ErrTransferNotFound, id)
+	}
+	var record TransferRecord
+	err := json.Unmarshal(data, &record)
+	if err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+func putTransfer(transfers *bbolt.Bucket, record *TransferRecord) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	return transfers.Put(idKey(record.Id), data)
+}
+
+// idKey encodes id big endian so keys sort in insertion order.
+func idKey(id uint64) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, id)
+	return key
+}
+
+func addressKey(address common.Address, id uint64) []byte {
+	return append(address.Bytes(), idKey(id)...)
+}
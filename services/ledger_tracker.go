@@ -0,0 +1,155 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"go.uber.org/zap"
+	"golang-ethereum-example-api/pkg/logging"
+	"golang-ethereum-example-api/pkg/settings"
+	"time"
+)
+
+type transferTracker struct {
+	client *ethclient.Client
+	ledger TransferLedger
+	config *settings.Ledger
+	logger *logging.LogWrapper
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewTransferTracker returns a worker that moves pending ledger records to their final status.
+// Stopping it closes the ledger.
+func NewTransferTracker(client *ethclient.Client, ledger TransferLedger, config *settings.Ledger, logger *logging.LogWrapper) Worker {
+	return &transferTracker{
+		client: client,
+		ledger: ledger,
+		config: config,
+		logger: logger,
+	}
+}
+
+func (t *transferTracker) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.cancel = cancel
+	t.done = make(chan struct{})
+	go func() {
+		defer close(t.done)
+		ticker := time.NewTicker(t.config.TrackInterval)
+		defer ticker.Stop()
+		for {
+			t.track(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	t.logger.Info("Transfer tracker started", zap.Duration("interval", t.config.TrackInterval))
+}
+
+func (t *transferTracker) Stop(ctx context.Context) error {
+	if t.cancel != nil {
+		t.cancel()
+		select {
+		case <-t.done:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return t.ledger.Close()
+}
+
+func (t *transferTracker) track(ctx context.Context) {
+	records, err := t.ledger.Pending()
+	if err != nil {
+		t.logger.Error("Transfer tracker loading pending transfers error", zap.Error(err))
+		return
+	}
+	if len(records) == 0 {
+		return
+	}
+	latest, err := t.client.BlockNumber(ctx)
+	if err != nil {
+		if ctx.Err() == nil {
+			t.logger.Warn("Transfer tracker getting latest block error", zap.Error(err))
+		}
+		return
+	}
+	nonces := make(map[common.Address]uint64)
+	for _, record := range records {
+		err = t.trackRecord(ctx, record, latest, nonces)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			t.logger.Warn("Transfer tracker error", zap.Error(err), zap.Uint64("id", record.Id), zap.String("hash", record.Hash.Hex()))
+		}
+	}
+}
+
+// trackRecord resolves a record that is not final yet. A mined transaction is final once it
+// is Confirmations blocks deep, until then a reorg may return it to the pool. A transaction
+// the node no longer knows was replaced when its nonce has been used since, otherwise it is
+// dropped once DropTimeout has passed.
+func (t *transferTracker) trackRecord(ctx context.Context, record *TransferRecord, latest uint64, nonces map[common.Address]uint64) error {
+	receipt, err := t.client.TransactionReceipt(ctx, record.Hash)
+	if err == nil {
+		blockNumber := receipt.BlockNumber.Uint64()
+		reverted := receipt.Status != types.ReceiptStatusSuccessful
+		if latest+1 >= blockNumber+t.config.Confirmations {
+			if reverted {
+				return t.ledger.UpdateStatus(record.Id, TransferStatusFailed, "execution reverted", &blockNumber)
+			}
+			return t.ledger.UpdateStatus(record.Id, TransferStatusConfirmed, "", &blockNumber)
+		}
+		if record.Status == TransferStatusMined && record.BlockNumber != nil && *record.BlockNumber == blockNumber {
+			return nil
+		}
+		var detail string
+		if reverted {
+			detail = "execution reverted"
+		}
+		return t.ledger.UpdateStatus(record.Id, TransferStatusMined, detail, &blockNumber)
+	}
+	if !errors.Is(err, ethereum.NotFound) {
+		return err
+	}
+
+	_, _, err = t.client.TransactionByHash(ctx, record.Hash)
+	if err == nil {
+		switch record.Status {
+		case TransferStatusMined:
+			return t.ledger.UpdateStatus(record.Id, TransferStatusPending, "removed from its block by a reorg", nil)
+		case TransferStatusSubmitting:
+			return t.ledger.UpdateStatus(record.Id, TransferStatusPending, "found in the node's transaction pool", nil)
+		}
+		return nil
+	}
+	if !errors.Is(err, ethereum.NotFound) {
+		return err
+	}
+
+	nonce, ok := nonces[record.From]
+	if !ok {
+		nonce, err = t.client.NonceAt(ctx, record.From, nil)
+		if err != nil {
+			return err
+		}
+		nonces[record.From] = nonce
+	}
+	if nonce > record.Nonce {
+		return t.ledger.UpdateStatus(record.Id, TransferStatusReplaced, fmt.Sprintf("nonce %d was used by another transaction", record.Nonce), nil)
+	}
+	if time.Since(record.CreatedAt) > t.config.DropTimeout {
+		return t.ledger.UpdateStatus(record.Id, TransferStatusDropped, "transaction is no longer known to the node", nil)
+	}
+	return nil
+}
@@ -0,0 +1,178 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"go.uber.org/zap"
+	"golang-ethereum-example-api/pkg/logging"
+	"golang-ethereum-example-api/pkg/settings"
+	"golang-ethereum-example-api/pkg/util"
+	"golang-ethereum-example-api/serializers"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const defaultLogLimit = 100
+
+type LogService interface {
+	GetLogs(ctx context.Context, request serializers.GetLogsRequest) (*serializers.GetLogsResponse, *util.ErrorInfo)
+}
+
+type logService struct {
+	client  *ethclient.Client
+	config  *settings.EthereumClient
+	decoder *eventDecoder
+	logger  *logging.LogWrapper
+}
+
+func NewLogService(client *ethclient.Client, config *settings.EthereumClient, registry ContractRegistry, logger *logging.LogWrapper) LogService {
+	return &logService{
+		client:  client,
+		config:  config,
+		decoder: newEventDecoder(registry),
+		logger:  logger,
+	}
+}
+
+// GetLogs scans the requested range in chunks of LogChunkSize blocks. A page ends when limit
+// logs were found or LogMaxScanBlocks blocks were scanned, and nextCursor resumes right after it.
+func (s *logService) GetLogs(ctx context.Context, request serializers.GetLogsRequest) (*serializers.GetLogsResponse, *util.ErrorInfo) {
+	fromBlock, errInfo := s.resolveBlockNumber(ctx, request.FromBlock, "earliest")
+	if errInfo != nil {
+		return nil, errInfo
+	}
+	toBlock, errInfo := s.resolveBlockNumber(ctx, request.ToBlock, "latest")
+	if errInfo != nil {
+		return nil, errInfo
+	}
+	if fromBlock > toBlock {
+		return nil, &util.ErrorInfo{
+			HttpCode: http.StatusBadRequest,
+			Message:  util.InvalidBlockErrorMessage,
+			Err:      errors.New("fromBlock is after toBlock"),
+		}
+	}
+
+	start, skipBefore := fromBlock, uint(0)
+	if request.Cursor != "" {
+		start, skipBefore, errInfo = parseLogCursor(request.Cursor, fromBlock, toBlock)
+		if errInfo != nil {
+			return nil, errInfo
+		}
+	}
+	limit := request.Limit
+	if limit == 0 {
+		limit = defaultLogLimit
+	}
+
+	query := filterQuery(request.LogFilter)
+
+	response := &serializers.GetLogsResponse{
+		FromBlock: fromBlock,
+		ToBlock:   toBlock,
+		Logs:      make([]serializers.LogResponse, 0),
+	}
+	cursorBlock := start
+	chunkSize := s.config.LogChunkSize
+	var scanned uint64
+	for start <= toBlock {
+		end := toBlock
+		if toBlock-start >= chunkSize {
+			end = start + chunkSize - 1
+		}
+		query.FromBlock = new(big.Int).SetUint64(start)
+		query.ToBlock = new(big.Int).SetUint64(end)
+		logs, err := s.client.FilterLogs(ctx, query)
+		var rpcErr rpc.Error
+		if errors.As(err, &rpcErr) && chunkSize > 1 {
+			// Providers reject ranges or result sets that are too large, so retry with a smaller chunk.
+			s.logger.Debug("GetLogs shrinking chunk", zap.Error(err), zap.Uint64("chunkSize", chunkSize))
+			chunkSize /= 2
+			continue
+		}
+		if err != nil {
+			s.logger.Error("GetLogs filter logs error", zap.Error(err), zap.Uint64("fromBlock", start), zap.Uint64("toBlock", end))
+			return nil, &util.ErrorInfo{
+				HttpCode: http.StatusInternalServerError,
+				Message:  util.InternalServiceErrorMessage,
+				Err:      err,
+			}
+		}
+
+		for _, log := range logs {
+			if log.BlockNumber == cursorBlock && log.Index < skipBefore {
+				continue
+			}
+			if len(response.Logs) == limit {
+				response.NextCursor = formatLogCursor(log.BlockNumber, log.Index)
+				return response, nil
+			}
+			response.Logs = append(response.Logs, s.decoder.logResponse(log))
+		}
+
+		scanned += end - start + 1
+		start = end + 1
+		if start <= toBlock && scanned >= s.config.LogMaxScanBlocks {
+			response.NextCursor = formatLogCursor(start, 0)
+			break
+		}
+	}
+	return response, nil
+}
+
+func (s *logService) resolveBlockNumber(ctx context.Context, block string, defaultBlock string) (uint64, *util.ErrorInfo) {
+	if block == "" {
+		block = defaultBlock
+	}
+	_, header, errInfo := resolveBlock(ctx, s.client, s.logger, block)
+	if errInfo != nil {
+		return 0, errInfo
+	}
+	return header.Number.Uint64(), nil
+}
+
+func filterQuery(filter serializers.LogFilter) ethereum.FilterQuery {
+	query := ethereum.FilterQuery{}
+	for _, address := range filter.Address {
+		query.Addresses = append(query.Addresses, common.HexToAddress(address))
+	}
+	for _, alternatives := range filter.Topics() {
+		var hashes []common.Hash
+		for _, hash := range alternatives {
+			hashes = append(hashes, common.HexToHash(hash))
+		}
+		query.Topics = append(query.Topics, hashes)
+	}
+	return query
+}
+
+func formatLogCursor(blockNumber uint64, logIndex uint) string {
+	return fmt.Sprintf("%d:%d", blockNumber, logIndex)
+}
+
+func parseLogCursor(cursor string, fromBlock uint64, toBlock uint64) (uint64, uint, *util.ErrorInfo) {
+	parts := strings.SplitN(cursor, ":", 2)
+	blockNumber, err := strconv.ParseUint(parts[0], 10, 64)
+	if err == nil && (blockNumber < fromBlock || blockNumber > toBlock) {
+		err = errors.New("cursor is outside of the requested block range")
+	}
+	var logIndex uint64
+	if err == nil {
+		logIndex, err = strconv.ParseUint(parts[1], 10, 32)
+	}
+	if err != nil {
+		return 0, 0, &util.ErrorInfo{
+			HttpCode: http.StatusBadRequest,
+			Message:  util.InvalidCursorErrorMessage,
+			Err:      err,
+		}
+	}
+	return blockNumber, uint(logIndex), nil
+}
@@ -0,0 +1,194 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"golang-ethereum-example-api/pkg/contracts"
+	"golang-ethereum-example-api/pkg/logging"
+	"golang-ethereum-example-api/pkg/util"
+	"golang-ethereum-example-api/serializers"
+	"math/big"
+	"net/http"
+	"strings"
+)
+
+const (
+	nftStandardErc721  = "erc721"
+	nftStandardErc1155 = "erc1155"
+)
+
+type NftService interface {
+	GetInterfaces(ctx context.Context, request serializers.GetNftInterfacesRequest) (*serializers.GetNftInterfacesResponse, *util.ErrorInfo)
+	GetOwner(ctx context.Context, request serializers.GetNftTokenRequest) (*serializers.GetNftOwnerResponse, *util.ErrorInfo)
+	GetTokenURI(ctx context.Context, request serializers.GetNftTokenRequest) (*serializers.GetNftTokenURIResponse, *util.ErrorInfo)
+	GetBalance(ctx context.Context, request serializers.GetNftBalanceRequest) (*serializers.GetNftBalanceResponse, *util.ErrorInfo)
+}
+
+type nftService struct {
+	client *ethclient.Client
+	logger *logging.LogWrapper
+}
+
+func NewNftService(client *ethclient.Client, logger *logging.LogWrapper) NftService {
+	return &nftService{client: client, logger: logger}
+}
+
+func (s *nftService) GetInterfaces(ctx context.Context, request serializers.GetNftInterfacesRequest) (*serializers.GetNftInterfacesResponse, *util.ErrorInfo) {
+	contract := common.HexToAddress(request.Contract)
+	response := &serializers.GetNftInterfacesResponse{Contract: request.Contract}
+	checks := []struct {
+		id     [4]byte
+		result *bool
+	}{
+		{contracts.InterfaceIDERC165, &response.Erc165},
+		{contracts.InterfaceIDERC721, &response.Erc721},
+		{contracts.InterfaceIDERC721Metadata, &response.Erc721Metadata},
+		{contracts.InterfaceIDERC1155, &response.Erc1155},
+		{contracts.InterfaceIDERC1155MetadataURI, &response.Erc1155MetadataURI},
+	}
+	for _, check := range checks {
+		supported, err := supportsInterface(ctx, s.client, contract, check.id)
+		if err != nil {
+			return nil, tokenCallError(s.logger, "GetNftInterfaces supportsInterface error", contract, err)
+		}
+		*check.result = supported
+	}
+	return response, nil
+}
+
+func (s *nftService) GetOwner(ctx context.Context, request serializers.GetNftTokenRequest) (*serializers.GetNftOwnerResponse, *util.ErrorInfo) {
+	contract := common.HexToAddress(request.Contract)
+	tokenId, _ := new(big.Int).SetString(request.TokenId, 10)
+	var out []interface{}
+	err := contracts.NewERC721(contract, s.client).Call(&bind.CallOpts{Context: ctx}, &out, "ownerOf", tokenId)
+	if err != nil {
+		return nil, tokenCallError(s.logger, "GetNftOwner ownerOf error", contract, err)
+	}
+	return &serializers.GetNftOwnerResponse{
+		Contract: request.Contract,
+		TokenId:  request.TokenId,
+		Owner:    out[0].(common.Address).Hex(),
+	}, nil
+}
+
+func (s *nftService) GetTokenURI(ctx context.Context, request serializers.GetNftTokenRequest) (*serializers.GetNftTokenURIResponse, *util.ErrorInfo) {
+	contract := common.HexToAddress(request.Contract)
+	tokenId, _ := new(big.Int).SetString(request.TokenId, 10)
+	standard, errInfo := detectNftStandard(ctx, s.client, s.logger, contract)
+	if errInfo != nil {
+		return nil, errInfo
+	}
+
+	var out []interface{}
+	var err error
+	if standard == nftStandardErc721 {
+		err = contracts.NewERC721(contract, s.client).Call(&bind.CallOpts{Context: ctx}, &out, "tokenURI", tokenId)
+	} else {
+		err = contracts.NewERC1155(contract, s.client).Call(&bind.CallOpts{Context: ctx}, &out, "uri", tokenId)
+	}
+	if err != nil {
+		return nil, tokenCallError(s.logger, "GetNftTokenURI error", contract, err)
+	}
+	uri := out[0].(string)
+	if standard == nftStandardErc1155 {
+		// ERC-1155 clients substitute {id} with the lowercase, zero padded hex token id.
+		uri = strings.ReplaceAll(uri, "{id}", fmt.Sprintf("%064x", tokenId))
+	}
+	return &serializers.GetNftTokenURIResponse{
+		Contract: request.Contract,
+		TokenId:  request.TokenId,
+		Standard: standard,
+		URI:      uri,
+	}, nil
+}
+
+func (s *nftService) GetBalance(ctx context.Context, request serializers.GetNftBalanceRequest) (*serializers.GetNftBalanceResponse, *util.ErrorInfo) {
+	owner := common.HexToAddress(request.Address)
+	contract := common.HexToAddress(request.Contract)
+	standard, errInfo := detectNftStandard(ctx, s.client, s.logger, contract)
+	if errInfo != nil {
+		return nil, errInfo
+	}
+	response := &serializers.GetNftBalanceResponse{
+		Address:  request.Address,
+		Contract: request.Contract,
+		TokenId:  request.TokenId,
+		Standard: standard,
+	}
+
+	var out []interface{}
+	var err error
+	switch {
+	case standard == nftStandardErc1155 && request.TokenId == "":
+		return nil, &util.ErrorInfo{
+			HttpCode: http.StatusBadRequest,
+			Message:  util.InvalidTokenIdErrorMessage,
+			Err:      errors.New("tokenId is required for ERC-1155 balances"),
+		}
+	case standard == nftStandardErc1155:
+		tokenId, _ := new(big.Int).SetString(request.TokenId, 10)
+		err = contracts.NewERC1155(contract, s.client).Call(&bind.CallOpts{Context: ctx}, &out, "balanceOf", owner, tokenId)
+	case request.TokenId != "":
+		// An ERC-721 token is either owned or not, so its balance is one or zero.
+		tokenId, _ := new(big.Int).SetString(request.TokenId, 10)
+		err = contracts.NewERC721(contract, s.client).Call(&bind.CallOpts{Context: ctx}, &out, "ownerOf", tokenId)
+		if err == nil {
+			balance := big.NewInt(0)
+			if out[0].(common.Address) == owner {
+				balance = big.NewInt(1)
+			}
+			out[0] = balance
+		}
+	default:
+		err = contracts.NewERC721(contract, s.client).Call(&bind.CallOpts{Context: ctx}, &out, "balanceOf", owner)
+	}
+	if err != nil {
+		return nil, tokenCallError(s.logger, "GetNftBalance balanceOf error", contract, err)
+	}
+	response.Balance = out[0].(*big.Int).String()
+	return response, nil
+}
+
+// supportsInterface reports whether contract implements the ERC-165 interface id. Contracts
+// without ERC-165 revert or return garbage, which is reported as unsupported.
+func supportsInterface(ctx context.Context, client *ethclient.Client, contract common.Address, id [4]byte) (bool, error) {
+	var out []interface{}
+	err := contracts.NewERC165(contract, client).Call(&bind.CallOpts{Context: ctx}, &out, "supportsInterface", id)
+	var rpcErr rpc.Error
+	if err != nil && (errors.As(err, &rpcErr) || isUnpackError(err)) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return out[0].(bool), nil
+}
+
+func detectNftStandard(ctx context.Context, client *ethclient.Client, logger *logging.LogWrapper, contract common.Address) (string, *util.ErrorInfo) {
+	for _, candidate := range []struct {
+		id       [4]byte
+		standard string
+	}{
+		{contracts.InterfaceIDERC721, nftStandardErc721},
+		{contracts.InterfaceIDERC1155, nftStandardErc1155},
+	} {
+		supported, err := supportsInterface(ctx, client, contract, candidate.id)
+		if err != nil {
+			return "", tokenCallError(logger, "Detecting NFT standard error", contract, err)
+		}
+		if supported {
+			return candidate.standard, nil
+		}
+	}
+	return "", &util.ErrorInfo{
+		HttpCode: http.StatusBadRequest,
+		Message:  util.InvalidTokenErrorMessage,
+		Detail:   "contract implements neither ERC-721 nor ERC-1155",
+		Err:      errors.New("unsupported nft contract"),
+	}
+}
@@ -0,0 +1,149 @@
+package services
+
+import (
+	"context"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"golang-ethereum-example-api/pkg/contracts"
+	"golang-ethereum-example-api/pkg/util"
+	"golang-ethereum-example-api/serializers"
+	"math/big"
+	"net/http"
+)
+
+func (s *transferService) TransferErc721(ctx context.Context, request serializers.TransferErc721Request) (*serializers.TransferNftResponse, *util.ErrorInfo) {
+	fromAccount := common.HexToAddress(request.FromAddress)
+	toAccount := common.HexToAddress(request.ToAddress)
+	contract := common.HexToAddress(request.ContractAddress)
+	tokenId, _ := new(big.Int).SetString(request.TokenId, 10)
+	data, errInfo := packNftCall(contracts.ERC721.Pack("safeTransferFrom", fromAccount, toAccount, tokenId, decodeHexData(request.Data)))
+	if errInfo != nil {
+		return nil, errInfo
+	}
+
+	response := &serializers.TransferNftResponse{
+		ContractAddress: request.ContractAddress,
+		Standard:        nftStandardErc721,
+		TokenIds:        []string{request.TokenId},
+	}
+	return s.sendNftTransfer(ctx, txRequest{
+		kind:                 transferKindErc721,
+		request:              request,
+		from:                 fromAccount,
+		privateKey:           request.PrivateKey,
+		to:                   &contract,
+		value:                new(big.Int),
+		data:                 data,
+		maxFeePerGas:         request.MaxFeePerGas,
+		maxPriorityFeePerGas: request.MaxPriorityFeePerGas,
+		gasLimit:             request.GasLimit,
+	}, response, request.WaitConfirmations, request.TimeoutSeconds)
+}
+
+func (s *transferService) TransferErc1155(ctx context.Context, request serializers.TransferErc1155Request) (*serializers.TransferNftResponse, *util.ErrorInfo) {
+	fromAccount := common.HexToAddress(request.FromAddress)
+	toAccount := common.HexToAddress(request.ToAddress)
+	contract := common.HexToAddress(request.ContractAddress)
+	tokenId, _ := new(big.Int).SetString(request.TokenId, 10)
+	amount, _ := new(big.Int).SetString(request.Amount, 10)
+	data, errInfo := packNftCall(contracts.ERC1155.Pack("safeTransferFrom", fromAccount, toAccount, tokenId, amount, decodeHexData(request.Data)))
+	if errInfo != nil {
+		return nil, errInfo
+	}
+
+	response := &serializers.TransferNftResponse{
+		ContractAddress: request.ContractAddress,
+		Standard:        nftStandardErc1155,
+		TokenIds:        []string{request.TokenId},
+		Amounts:         []string{request.Amount},
+	}
+	return s.sendNftTransfer(ctx, txRequest{
+		kind:                 transferKindErc1155,
+		request:              request,
+		from:                 fromAccount,
+		privateKey:           request.PrivateKey,
+		to:                   &contract,
+		value:                new(big.Int),
+		data:                 data,
+		maxFeePerGas:         request.MaxFeePerGas,
+		maxPriorityFeePerGas: request.MaxPriorityFeePerGas,
+		gasLimit:             request.GasLimit,
+	}, response, request.WaitConfirmations, request.TimeoutSeconds)
+}
+
+func (s *transferService) TransferErc1155Batch(ctx context.Context, request serializers.TransferErc1155BatchRequest) (*serializers.TransferNftResponse, *util.ErrorInfo) {
+	fromAccount := common.HexToAddress(request.FromAddress)
+	toAccount := common.HexToAddress(request.ToAddress)
+	contract := common.HexToAddress(request.ContractAddress)
+	tokenIds := make([]*big.Int, len(request.TokenIds))
+	amounts := make([]*big.Int, len(request.Amounts))
+	for i := range request.TokenIds {
+		tokenIds[i], _ = new(big.Int).SetString(request.TokenIds[i], 10)
+		amounts[i], _ = new(big.Int).SetString(request.Amounts[i], 10)
+	}
+	data, errInfo := packNftCall(contracts.ERC1155.Pack("safeBatchTransferFrom", fromAccount, toAccount, tokenIds, amounts, decodeHexData(request.Data)))
+	if errInfo != nil {
+		return nil, errInfo
+	}
+
+	response := &serializers.TransferNftResponse{
+		ContractAddress: request.ContractAddress,
+		Standard:        nftStandardErc1155,
+		TokenIds:        request.TokenIds,
+		Amounts:         request.Amounts,
+	}
+	return s.sendNftTransfer(ctx, txRequest{
+		kind:                 transferKindErc1155Batch,
+		request:              request,
+		from:                 fromAccount,
+		privateKey:           request.PrivateKey,
+		to:                   &contract,
+		value:                new(big.Int),
+		data:                 data,
+		maxFeePerGas:         request.MaxFeePerGas,
+		maxPriorityFeePerGas: request.MaxPriorityFeePerGas,
+		gasLimit:             request.GasLimit,
+	}, response, request.WaitConfirmations, request.TimeoutSeconds)
+}
+
+// sendNftTransfer simulates the transfer first, so a missing approval or a receiver that
+// rejects the token is reported with its revert reason instead of being mined as a failure.
+func (s *transferService) sendNftTransfer(ctx context.Context, request txRequest, response *serializers.TransferNftResponse, waitConfirmations uint64, timeoutSeconds uint64) (*serializers.TransferNftResponse, *util.ErrorInfo) {
+	_, errInfo := s.simulateCall(ctx, ethereum.CallMsg{From: request.from, To: request.to, Data: request.data})
+	if errInfo != nil {
+		return nil, errInfo
+	}
+
+	signedTx, fees, errInfo := s.sendTransaction(ctx, request)
+	if errInfo != nil {
+		return nil, errInfo
+	}
+	response.TransactionHash = signedTx.Hash().Hex()
+	response.GasLimit = signedTx.Gas()
+	response.TransactionFees = fees.serialize()
+	response.Receipt, errInfo = s.awaitReceipt(ctx, signedTx, waitConfirmations, timeoutSeconds)
+	if errInfo != nil {
+		return nil, errInfo
+	}
+	return response, nil
+}
+
+func packNftCall(data []byte, err error) ([]byte, *util.ErrorInfo) {
+	if err != nil {
+		return nil, &util.ErrorInfo{
+			HttpCode: http.StatusInternalServerError,
+			Message:  util.InternalServiceErrorMessage,
+			Err:      err,
+		}
+	}
+	return data, nil
+}
+
+// decodeHexData decodes optional call data that has already been validated by the serializer.
+func decodeHexData(data string) []byte {
+	if data == "" {
+		return []byte{}
+	}
+	return hexutil.MustDecode(data)
+}
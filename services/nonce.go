@@ -0,0 +1,188 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"go.uber.org/zap"
+	"golang-ethereum-example-api/pkg/logging"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// NonceManager hands out nonces locally so concurrent sends from the same account do not
+// collide on the node's pending nonce.
+type NonceManager interface {
+	Acquire(ctx context.Context, address common.Address) (*NonceLease, error)
+	// MarkUsed records a nonce taken by a transaction that was signed and sent without a
+	// lease, so it is not handed out again.
+	MarkUsed(address common.Address, nonce uint64)
+}
+
+// NonceLease is a nonce reserved for a single transaction. Exactly one of Commit or Release
+// must be called once the broadcast outcome is known. Release takes the broadcast error, or
+// nil when the transaction never left the process.
+type NonceLease struct {
+	Nonce   uint64
+	address common.Address
+	manager *nonceManager
+}
+
+func (l *NonceLease) Commit() {
+	l.manager.commit(l.address, l.Nonce)
+}
+
+func (l *NonceLease) Release(err error) {
+	l.manager.release(l.address, l.Nonce, err)
+}
+
+type accountNonces struct {
+	mu       sync.Mutex
+	next     uint64
+	lastSync time.Time
+	inFlight map[uint64]struct{}
+	released []uint64
+}
+
+type nonceManager struct {
+	client         *ethclient.Client
+	resyncInterval time.Duration
+	logger         *logging.LogWrapper
+
+	mu       sync.Mutex
+	accounts map[common.Address]*accountNonces
+}
+
+func NewNonceManager(client *ethclient.Client, resyncInterval time.Duration, logger *logging.LogWrapper) NonceManager {
+	return &nonceManager{
+		client:         client,
+		resyncInterval: resyncInterval,
+		logger:         logger,
+		accounts:       make(map[common.Address]*accountNonces),
+	}
+}
+
+func (m *nonceManager) account(address common.Address) *accountNonces {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	account, ok := m.accounts[address]
+	if !ok {
+		account = &accountNonces{inFlight: make(map[uint64]struct{})}
+		m.accounts[address] = account
+	}
+	return account
+}
+
+func (m *nonceManager) Acquire(ctx context.Context, address common.Address) (*NonceLease, error) {
+	account := m.account(address)
+	account.mu.Lock()
+	defer account.mu.Unlock()
+
+	// Reconcile with the node whenever nothing is in flight, so transactions sent by other
+	// wallets or dropped from the mempool are picked up. A release that left the nonce in
+	// doubt forces the resync even with other transactions in flight.
+	forced := account.lastSync.IsZero()
+	if forced || len(account.inFlight) == 0 && time.Since(account.lastSync) >= m.resyncInterval {
+		if err := m.resync(ctx, address, account); err != nil {
+			return nil, err
+		}
+	}
+
+	var nonce uint64
+	if len(account.released) > 0 {
+		nonce = account.released[0]
+		account.released = account.released[1:]
+	} else {
+		nonce = account.next
+		account.next++
+	}
+	account.inFlight[nonce] = struct{}{}
+	return &NonceLease{Nonce: nonce, address: address, manager: m}, nil
+}
+
+func (m *nonceManager) MarkUsed(address common.Address, nonce uint64) {
+	account := m.account(address)
+	account.mu.Lock()
+	defer account.mu.Unlock()
+	if nonce >= account.next {
+		account.next = nonce + 1
+	}
+	account.released = slices.DeleteFunc(account.released, func(released uint64) bool {
+		return released == nonce
+	})
+}
+
+func (m *nonceManager) resync(ctx context.Context, address common.Address, account *accountNonces) error {
+	pending, err := m.client.PendingNonceAt(ctx, address)
+	if err != nil {
+		return err
+	}
+	// The node may not have seen nonces still in flight yet, they must not be handed out again.
+	next := pending
+	for nonce := range account.inFlight {
+		next = max(next, nonce+1)
+	}
+	if next != account.next {
+		m.logger.Info("NonceManager resynced nonce", zap.String("address", address.Hex()),
+			zap.Uint64("localNonce", account.next), zap.Uint64("pendingNonce", pending),
+			zap.Uint64("nextNonce", next))
+	}
+	account.next = next
+	// Released nonces below the pending nonce were used elsewhere, the others are still gaps.
+	account.released = slices.DeleteFunc(account.released, func(released uint64) bool {
+		return released < pending
+	})
+	account.lastSync = time.Now()
+	return nil
+}
+
+func (m *nonceManager) commit(address common.Address, nonce uint64) {
+	account := m.account(address)
+	account.mu.Lock()
+	defer account.mu.Unlock()
+	delete(account.inFlight, nonce)
+}
+
+// nonceInUseErrors are node rejections meaning the nonce is already taken by a transaction
+// in the chain or the mempool.
+var nonceInUseErrors = []string{"nonce too low", "already known", "replacement transaction underpriced"}
+
+// release returns a nonce whose transaction never reached the node or was rejected by it.
+// When the node rejected it as already used, or the outcome is unknown because the request
+// failed in transit, the next acquisition resyncs from the node instead of reusing it.
+func (m *nonceManager) release(address common.Address, nonce uint64, err error) {
+	account := m.account(address)
+	account.mu.Lock()
+	defer account.mu.Unlock()
+	delete(account.inFlight, nonce)
+
+	if err != nil && (!isNodeRejection(err) || isNonceInUse(err)) {
+		account.lastSync = time.Time{}
+		return
+	}
+	account.released = append(account.released, nonce)
+	sort.Slice(account.released, func(i, j int) bool { return account.released[i] < account.released[j] })
+	// Shrink the counter instead of keeping released nonces at the top of the range.
+	for len(account.released) > 0 && account.released[len(account.released)-1]+1 == account.next {
+		account.released = account.released[:len(account.released)-1]
+		account.next--
+	}
+}
+
+func isNonceInUse(err error) bool {
+	return slices.ContainsFunc(nonceInUseErrors, func(message string) bool {
+		return strings.Contains(err.Error(), message)
+	})
+}
+
+// isNodeRejection reports whether err is an answer from the node. Any other error, such as a
+// timeout or a dropped connection, leaves open whether the node accepted the transaction.
+func isNodeRejection(err error) bool {
+	var rpcErr rpc.Error
+	return errors.As(err, &rpcErr)
+}
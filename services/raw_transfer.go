@@ -0,0 +1,285 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"go.uber.org/zap"
+	"golang-ethereum-example-api/pkg/util"
+	"golang-ethereum-example-api/serializers"
+	"math/big"
+	"net/http"
+	"strings"
+)
+
+// PrepareTransfer builds the same transaction SendEthereum would sign, for a signer that is
+// not reachable from the API.
+func (s *transferService) PrepareTransfer(ctx context.Context, request serializers.PrepareTransferRequest) (*serializers.PrepareTransferResponse, *util.ErrorInfo) {
+	fromAccount := common.HexToAddress(request.FromAddress)
+	toAccount := common.HexToAddress(request.ToAddress)
+	amount, err := util.ParseAmount(request.EthereumAmount, request.Unit)
+	if err != nil {
+		return nil, &util.ErrorInfo{
+			HttpCode: http.StatusBadRequest,
+			Message:  util.InvalidAmountErrorMessage,
+			Err:      err,
+		}
+	}
+
+	fees, errInfo := s.suggestFees(ctx, request.MaxFeePerGas, request.MaxPriorityFeePerGas)
+	if errInfo != nil {
+		return nil, errInfo
+	}
+
+	gasLimit, errInfo := s.estimateGas(ctx, fees.callMsg(fromAccount, &toAccount, amount, nil), request.GasLimit)
+	if errInfo != nil {
+		return nil, errInfo
+	}
+
+	// The nonce manager is left alone, a transaction that may never be signed must not hold
+	// back the sends of a managed account.
+	nonce, err := s.client.PendingNonceAt(ctx, fromAccount)
+	if err != nil {
+		s.logger.Error("PrepareTransfer getting nonce error", zap.Error(err))
+		return nil, &util.ErrorInfo{
+			HttpCode: http.StatusInternalServerError,
+			Message:  util.InternalServiceErrorMessage,
+			Err:      err,
+		}
+	}
+
+	tx := fees.newTx(s.chainID, nonce, &toAccount, amount, gasLimit, nil)
+	unsigned, err := tx.MarshalBinary()
+	if err != nil {
+		s.logger.Error("PrepareTransfer encoding transaction error", zap.Error(err))
+		return nil, &util.ErrorInfo{
+			HttpCode: http.StatusInternalServerError,
+			Message:  util.InternalServiceErrorMessage,
+			Err:      err,
+		}
+	}
+	txJson, err := tx.MarshalJSON()
+	if err != nil {
+		s.logger.Error("PrepareTransfer encoding transaction error", zap.Error(err))
+		return nil, &util.ErrorInfo{
+			HttpCode: http.StatusInternalServerError,
+			Message:  util.InternalServiceErrorMessage,
+			Err:      err,
+		}
+	}
+	return &serializers.PrepareTransferResponse{
+		From:                fromAccount.Hex(),
+		To:                  toAccount.Hex(),
+		ChainId:             s.chainID.String(),
+		Nonce:               tx.Nonce(),
+		Value:               amount.String(),
+		GasLimit:            gasLimit,
+		UnsignedTransaction: hexutil.Encode(unsigned),
+		Transaction:         txJson,
+		SigningHash:         types.LatestSignerForChainID(s.chainID).Hash(tx).Hex(),
+		TransactionFees:     fees.serialize(),
+	}, nil
+}
+
+// SendRawTransaction broadcasts a transaction signed outside the API, given either encoded
+// or as an unsigned transaction plus signature. It is checked against the node first, so a
+// wrong chain, an unusable nonce or an unfunded sender is reported as a bad request rather
+// than a node error.
+func (s *transferService) SendRawTransaction(ctx context.Context, request serializers.SendRawTransactionRequest) (*serializers.SendRawTransactionResponse, *util.ErrorInfo) {
+	encoded := request.RawTransaction
+	if request.UnsignedTransaction != "" {
+		encoded = request.UnsignedTransaction
+	}
+	raw, _ := hexutil.Decode(encoded)
+	tx := new(types.Transaction)
+	err := tx.UnmarshalBinary(raw)
+	if err != nil {
+		return nil, &util.ErrorInfo{
+			HttpCode: http.StatusBadRequest,
+			Message:  util.InvalidRawTransactionErrorMessage,
+			Detail:   err.Error(),
+			Err:      err,
+		}
+	}
+	if request.UnsignedTransaction != "" {
+		var errInfo *util.ErrorInfo
+		tx, errInfo = s.applySignature(tx, request.R, request.S, *request.V)
+		if errInfo != nil {
+			return nil, errInfo
+		}
+	}
+	return s.sendSignedTransaction(ctx, tx, transferKindRaw, request, request.WaitConfirmations, request.TimeoutSeconds)
+}
+
+// applySignature attaches r, s and v to an unsigned transaction. v is accepted as the
+// recovery id, in the 27/28 form and, for legacy transactions, in the EIP-155 form.
+func (s *transferService) applySignature(tx *types.Transaction, r string, sValue string, v uint64) (*types.Transaction, *util.ErrorInfo) {
+	if tx.Type() == types.LegacyTxType && s.chainID.IsUint64() && v >= 35 {
+		v -= s.chainID.Uint64()*2 + 35
+	} else if v >= 27 {
+		v -= 27
+	}
+	if v > 1 {
+		return nil, &util.ErrorInfo{
+			HttpCode: http.StatusBadRequest,
+			Message:  util.InvalidSignatureErrorMessage,
+			Err:      errors.New("v is not a valid recovery id"),
+		}
+	}
+	signature := make([]byte, crypto.SignatureLength)
+	signatureValue(r).FillBytes(signature[:32])
+	signatureValue(sValue).FillBytes(signature[32:64])
+	signature[64] = byte(v)
+	signed, err := tx.WithSignature(types.LatestSignerForChainID(s.chainID), signature)
+	if err != nil {
+		return nil, &util.ErrorInfo{
+			HttpCode: http.StatusBadRequest,
+			Message:  util.InvalidSignatureErrorMessage,
+			Err:      err,
+		}
+	}
+	return signed, nil
+}
+
+func (s *transferService) sendSignedTransaction(ctx context.Context, tx *types.Transaction, kind string, request interface{}, waitConfirmations uint64, timeoutSeconds uint64) (*serializers.SendRawTransactionResponse, *util.ErrorInfo) {
+	if !tx.Protected() {
+		return nil, &util.ErrorInfo{
+			HttpCode: http.StatusBadRequest,
+			Message:  util.InvalidChainIdErrorMessage,
+			Err:      errors.New("transaction is not replay protected"),
+		}
+	}
+	if tx.ChainId().Cmp(s.chainID) != 0 {
+		return nil, &util.ErrorInfo{
+			HttpCode: http.StatusBadRequest,
+			Message:  util.InvalidChainIdErrorMessage,
+			Detail:   fmt.Sprintf("transaction is for chain %s, node is on chain %s", tx.ChainId(), s.chainID),
+			Err:      errors.New("chain id mismatch"),
+		}
+	}
+	from, err := types.Sender(types.LatestSignerForChainID(s.chainID), tx)
+	if err != nil {
+		return nil, &util.ErrorInfo{
+			HttpCode: http.StatusBadRequest,
+			Message:  util.InvalidSignatureErrorMessage,
+			Err:      err,
+		}
+	}
+
+	errInfo := s.checkSignedNonce(ctx, tx, from)
+	if errInfo != nil {
+		return nil, errInfo
+	}
+	// The node checks the balance against the latest state, which still includes the funds of
+	// a pending transaction this one replaces.
+	balance, err := s.client.BalanceAt(ctx, from, nil)
+	if err != nil {
+		s.logger.Error("SendRawTransaction getting balance error", zap.Error(err))
+		return nil, &util.ErrorInfo{
+			HttpCode: http.StatusInternalServerError,
+			Message:  util.InternalServiceErrorMessage,
+			Err:      err,
+		}
+	}
+	if balance.Cmp(tx.Cost()) < 0 {
+		return nil, &util.ErrorInfo{
+			HttpCode: http.StatusBadRequest,
+			Message:  util.InsufficientBalanceErrorMessage,
+			Detail:   fmt.Sprintf("balance is %s wei, value plus max fee is %s wei", balance, tx.Cost()),
+			Err:      errors.New("insufficient balance"),
+		}
+	}
+
+	errInfo = s.broadcast(ctx, txRequest{kind: kind, request: request, from: from, to: tx.To()}, tx)
+	if errInfo != nil {
+		return nil, errInfo
+	}
+	s.nonces.MarkUsed(from, tx.Nonce())
+
+	response := &serializers.SendRawTransactionResponse{
+		TransactionHash: tx.Hash().Hex(),
+		From:            from.Hex(),
+		ChainId:         tx.ChainId().String(),
+		Nonce:           tx.Nonce(),
+		Value:           tx.Value().String(),
+		GasLimit:        tx.Gas(),
+		TransactionFees: signedTxFees(tx),
+	}
+	if tx.To() != nil {
+		response.To = tx.To().Hex()
+	}
+	if len(tx.Data()) > 0 {
+		response.Data = hexutil.Encode(tx.Data())
+	}
+	response.Receipt, errInfo = s.awaitReceipt(ctx, tx, waitConfirmations, timeoutSeconds)
+	if errInfo != nil {
+		return nil, errInfo
+	}
+	return response, nil
+}
+
+// checkSignedNonce accepts nonces from the sender's next confirmed nonce up to its pending
+// nonce, which allows replacing a pending transaction but not leaving a gap.
+func (s *transferService) checkSignedNonce(ctx context.Context, tx *types.Transaction, from common.Address) *util.ErrorInfo {
+	pending, err := s.client.PendingNonceAt(ctx, from)
+	if err != nil {
+		s.logger.Error("SendRawTransaction getting pending nonce error", zap.Error(err), zap.String("from", from.Hex()))
+		return &util.ErrorInfo{
+			HttpCode: http.StatusInternalServerError,
+			Message:  util.InternalServiceErrorMessage,
+			Err:      err,
+		}
+	}
+	if tx.Nonce() > pending {
+		return &util.ErrorInfo{
+			HttpCode: http.StatusBadRequest,
+			Message:  util.InvalidNonceErrorMessage,
+			Detail:   fmt.Sprintf("nonce %d is ahead of the pending nonce %d", tx.Nonce(), pending),
+			Err:      errors.New("nonce gap"),
+		}
+	}
+	if tx.Nonce() == pending {
+		return nil
+	}
+	confirmed, err := s.client.NonceAt(ctx, from, nil)
+	if err != nil {
+		s.logger.Error("SendRawTransaction getting nonce error", zap.Error(err), zap.String("from", from.Hex()))
+		return &util.ErrorInfo{
+			HttpCode: http.StatusInternalServerError,
+			Message:  util.InternalServiceErrorMessage,
+			Err:      err,
+		}
+	}
+	if tx.Nonce() < confirmed {
+		return &util.ErrorInfo{
+			HttpCode: http.StatusBadRequest,
+			Message:  util.InvalidNonceErrorMessage,
+			Detail:   fmt.Sprintf("nonce %d is already used, the confirmed nonce is %d", tx.Nonce(), confirmed),
+			Err:      errors.New("nonce too low"),
+		}
+	}
+	return nil
+}
+
+// signatureValue parses r or s, which are validated as hex of at most 32 bytes.
+func signatureValue(value string) *big.Int {
+	parsed, _ := new(big.Int).SetString(strings.TrimPrefix(value, "0x"), 16)
+	return parsed
+}
+
+// signedTxFees describes the fee fields of a decoded transaction.
+func signedTxFees(tx *types.Transaction) serializers.TransactionFees {
+	fees := serializers.TransactionFees{TransactionType: transactionTypeName(tx.Type())}
+	switch tx.Type() {
+	case types.LegacyTxType, types.AccessListTxType:
+		fees.GasPrice = tx.GasPrice().String()
+	default:
+		fees.MaxFeePerGas = tx.GasFeeCap().String()
+		fees.MaxPriorityFeePerGas = tx.GasTipCap().String()
+	}
+	return fees
+}
@@ -0,0 +1,195 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+	"go.uber.org/zap"
+	"golang-ethereum-example-api/pkg/util"
+	"golang-ethereum-example-api/serializers"
+	"math/big"
+	"net/http"
+)
+
+// replacementFeeBump is the percentage both fee caps have to rise by for the node to accept a
+// transaction with the same nonce.
+const replacementFeeBump = 10
+
+func (s *transferService) SpeedUpTransaction(ctx context.Context, request serializers.ReplaceTransactionRequest) (*serializers.ReplaceTransactionResponse, *util.ErrorInfo) {
+	return s.replaceTransaction(ctx, request, transferKindSpeedUp)
+}
+
+func (s *transferService) CancelTransaction(ctx context.Context, request serializers.ReplaceTransactionRequest) (*serializers.ReplaceTransactionResponse, *util.ErrorInfo) {
+	return s.replaceTransaction(ctx, request, transferKindCancel)
+}
+
+// replaceTransaction signs a new transaction with the nonce of a pending one. A speed up
+// resends the same call, a cancel sends nothing to the sender itself.
+func (s *transferService) replaceTransaction(ctx context.Context, request serializers.ReplaceTransactionRequest, kind string) (*serializers.ReplaceTransactionResponse, *util.ErrorInfo) {
+	hash := common.HexToHash(request.Hash)
+	original, isPending, err := s.client.TransactionByHash(ctx, hash)
+	if errors.Is(err, ethereum.NotFound) {
+		return nil, &util.ErrorInfo{
+			HttpCode: http.StatusNotFound,
+			Message:  util.TransactionNotFoundErrorMessage,
+			Err:      err,
+		}
+	}
+	if err != nil {
+		s.logger.Error("ReplaceTransaction getting transaction error", zap.Error(err), zap.String("hash", request.Hash))
+		return nil, &util.ErrorInfo{
+			HttpCode: http.StatusInternalServerError,
+			Message:  util.InternalServiceErrorMessage,
+			Err:      err,
+		}
+	}
+	if !isPending {
+		return nil, &util.ErrorInfo{
+			HttpCode: http.StatusConflict,
+			Message:  util.TransactionAlreadyMinedErrorMessage,
+			Err:      errors.New("only pending transactions can be replaced"),
+		}
+	}
+	// Access list and blob transactions would need their own replacement rules, a blob
+	// transaction can only be replaced by another one carrying the same blobs.
+	if original.Type() != types.LegacyTxType && original.Type() != types.DynamicFeeTxType {
+		return nil, &util.ErrorInfo{
+			HttpCode: http.StatusBadRequest,
+			Message:  util.UnsupportedTransactionTypeErrorMessage,
+			Detail:   fmt.Sprintf("transactions of type %d cannot be replaced", original.Type()),
+			Err:      errors.New("unsupported transaction type"),
+		}
+	}
+	from, err := types.Sender(types.LatestSignerForChainID(s.chainID), original)
+	if err != nil {
+		return nil, &util.ErrorInfo{
+			HttpCode: http.StatusBadRequest,
+			Message:  util.InvalidTransactionHashErrorMessage,
+			Err:      err,
+		}
+	}
+
+	txSigner, errInfo := s.resolveSigner(ctx, from, request.PrivateKey)
+	if errInfo != nil {
+		return nil, errInfo
+	}
+	fees, errInfo := s.suggestFees(ctx, request.MaxFeePerGas, request.MaxPriorityFeePerGas)
+	if errInfo != nil {
+		return nil, errInfo
+	}
+	errInfo = raiseReplacementFees(fees, original, request.MaxFeePerGas != "", request.MaxPriorityFeePerGas != "")
+	if errInfo != nil {
+		return nil, errInfo
+	}
+
+	replacement := txRequest{
+		kind: kind,
+		// The hash is only part of the path, so it is added for the ledger.
+		request: struct {
+			OriginalTransactionHash string `json:"originalTransactionHash"`
+			serializers.ReplaceTransactionRequest
+		}{original.Hash().Hex(), request},
+		from:  from,
+		to:    original.To(),
+		value: original.Value(),
+		data:  original.Data(),
+	}
+	gasLimit := original.Gas()
+	if kind == transferKindCancel {
+		replacement.to = &from
+		replacement.value = new(big.Int)
+		replacement.data = nil
+		gasLimit = params.TxGas
+	}
+
+	tx := fees.newTx(s.chainID, original.Nonce(), replacement.to, replacement.value, gasLimit, replacement.data)
+	// A sped up call keeps the storage slots it pre-declared, without them it may run out of gas.
+	if kind == transferKindSpeedUp && fees.isDynamic() && len(original.AccessList()) > 0 {
+		tx = types.NewTx(&types.DynamicFeeTx{
+			ChainID:    s.chainID,
+			Nonce:      original.Nonce(),
+			GasTipCap:  fees.gasTipCap,
+			GasFeeCap:  fees.gasFeeCap,
+			Gas:        gasLimit,
+			To:         replacement.to,
+			Value:      replacement.value,
+			Data:       replacement.data,
+			AccessList: original.AccessList(),
+		})
+	}
+	signedTx, err := txSigner.SignTx(ctx, tx, s.chainID)
+	if err != nil {
+		s.logger.Error("ReplaceTransaction sign transaction error", zap.Error(err))
+		return nil, &util.ErrorInfo{
+			HttpCode: http.StatusInternalServerError,
+			Message:  util.InternalServiceErrorMessage,
+			Err:      err,
+		}
+	}
+	errInfo = s.broadcast(ctx, replacement, signedTx)
+	if errInfo != nil {
+		return nil, errInfo
+	}
+
+	response := &serializers.ReplaceTransactionResponse{
+		OriginalTransactionHash: original.Hash().Hex(),
+		TransactionHash:         signedTx.Hash().Hex(),
+		Nonce:                   signedTx.Nonce(),
+		GasLimit:                signedTx.Gas(),
+		TransactionFees:         fees.serialize(),
+	}
+	response.Receipt, errInfo = s.awaitReceipt(ctx, signedTx, request.WaitConfirmations, request.TimeoutSeconds)
+	if errInfo != nil {
+		return nil, errInfo
+	}
+	return response, nil
+}
+
+// raiseReplacementFees lifts suggested fees to the replacement minimum of original. Caps the
+// caller set explicitly are never changed, so a cap below the minimum is an error.
+func raiseReplacementFees(fees *txFees, original *types.Transaction, explicitFeeCap bool, explicitTipCap bool) *util.ErrorInfo {
+	minFeeCap := bumpFee(original.GasFeeCap())
+	minTipCap := bumpFee(original.GasTipCap())
+	tooLow := &util.ErrorInfo{
+		HttpCode: http.StatusBadRequest,
+		Message:  util.InvalidFeeErrorMessage,
+		Detail:   fmt.Sprintf("replacement requires maxFeePerGas >= %s and maxPriorityFeePerGas >= %s", minFeeCap, minTipCap),
+		Err:      errors.New("replacement fees too low"),
+	}
+
+	if !fees.isDynamic() {
+		if fees.gasPrice.Cmp(minFeeCap) < 0 {
+			fees.gasPrice = minFeeCap
+		}
+		return nil
+	}
+	if fees.gasFeeCap.Cmp(minFeeCap) < 0 {
+		if explicitFeeCap {
+			return tooLow
+		}
+		fees.gasFeeCap = minFeeCap
+	}
+	if fees.gasTipCap.Cmp(minTipCap) < 0 {
+		if explicitTipCap {
+			return tooLow
+		}
+		fees.gasTipCap = minTipCap
+	}
+	if fees.gasTipCap.Cmp(fees.gasFeeCap) > 0 {
+		if explicitFeeCap {
+			return tooLow
+		}
+		fees.gasFeeCap = new(big.Int).Set(fees.gasTipCap)
+	}
+	return nil
+}
+
+// bumpFee returns fee raised by replacementFeeBump percent, rounded up.
+func bumpFee(fee *big.Int) *big.Int {
+	bumped := new(big.Int).Mul(fee, big.NewInt(100+replacementFeeBump))
+	return bumped.Add(bumped, big.NewInt(99)).Div(bumped, big.NewInt(100))
+}
@@ -0,0 +1,75 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+	"go.uber.org/zap"
+	"golang-ethereum-example-api/pkg/util"
+	"net/http"
+	"strings"
+)
+
+// isRevert reports whether the node answered a call with a revert, as opposed to failing to
+// execute it at all.
+func isRevert(err error) bool {
+	var rpcErr rpc.Error
+	return errors.As(err, &rpcErr) && strings.Contains(strings.ToLower(rpcErr.Error()), "revert")
+}
+
+// revertReason extracts the Error(string) reason from a reverted call. It falls back to the
+// node's message when the revert data is missing or is a custom error.
+func revertReason(err error) string {
+	data, ok := revertData(err)
+	if ok {
+		reason, unpackErr := abi.UnpackRevert(data)
+		if unpackErr == nil {
+			return reason
+		}
+	}
+	return err.Error()
+}
+
+// revertData returns the raw revert payload attached to an rpc error, if the node sent one.
+func revertData(err error) ([]byte, bool) {
+	var dataErr rpc.DataError
+	if !errors.As(err, &dataErr) {
+		return nil, false
+	}
+	hexData, ok := dataErr.ErrorData().(string)
+	if !ok {
+		return nil, false
+	}
+	data, decodeErr := hexutil.Decode(hexData)
+	if decodeErr != nil {
+		return nil, false
+	}
+	return data, true
+}
+
+// simulateCall runs msg with eth_call against the pending state so reverts are reported to the
+// caller before anything is broadcast.
+func (s *transferService) simulateCall(ctx context.Context, msg ethereum.CallMsg) ([]byte, *util.ErrorInfo) {
+	output, err := s.client.PendingCallContract(ctx, msg)
+	if err == nil {
+		return output, nil
+	}
+	var rpcErr rpc.Error
+	if errors.As(err, &rpcErr) {
+		return nil, &util.ErrorInfo{
+			HttpCode: http.StatusBadRequest,
+			Message:  util.ExecutionRevertedErrorMessage,
+			Detail:   revertReason(err),
+			Err:      err,
+		}
+	}
+	s.logger.Error("Simulating call error", zap.Error(err))
+	return nil, &util.ErrorInfo{
+		HttpCode: http.StatusInternalServerError,
+		Message:  util.InternalServiceErrorMessage,
+		Err:      err,
+	}
+}
@@ -0,0 +1,433 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"go.uber.org/zap"
+	"golang-ethereum-example-api/pkg/logging"
+	"golang-ethereum-example-api/pkg/util"
+	"golang-ethereum-example-api/serializers"
+	"net/http"
+	"reflect"
+	"slices"
+	"sync"
+	"time"
+)
+
+const (
+	streamBufferSize = 64
+	streamMinBackoff = time.Second
+	streamMaxBackoff = 30 * time.Second
+	streamOverlap    = 2 * time.Second
+)
+
+type DialFunc func(ctx context.Context) (*ethclient.Client, error)
+
+type StreamService interface {
+	SubscribeHeads(ctx context.Context) (<-chan serializers.HeadResponse, *util.ErrorInfo)
+	SubscribeLogs(ctx context.Context, request serializers.StreamLogsRequest) (<-chan serializers.LogResponse, *util.ErrorInfo)
+}
+
+type streamService struct {
+	heads   *broadcaster[*types.Header]
+	logs    *broadcaster[types.Log]
+	decoder *eventDecoder
+}
+
+// NewStreamService fans node subscriptions out to API clients. A nil dial disables streaming.
+func NewStreamService(dial DialFunc, registry ContractRegistry, logger *logging.LogWrapper) StreamService {
+	s := &streamService{decoder: newEventDecoder(registry)}
+	if dial == nil {
+		return s
+	}
+	s.heads = newBroadcaster("heads", dial, func(ctx context.Context, client *ethclient.Client, _ ethereum.FilterQuery, ch chan<- *types.Header) (ethereum.Subscription, error) {
+		return client.SubscribeNewHead(ctx, ch)
+	}, nil, logger)
+	// The upstream subscription receives the logs matching any client's filter and each client
+	// filters its own copy, so clients with different filters still share one node subscription.
+	s.logs = newBroadcaster("logs", dial, func(ctx context.Context, client *ethclient.Client, query ethereum.FilterQuery, ch chan<- types.Log) (ethereum.Subscription, error) {
+		return client.SubscribeFilterLogs(ctx, query, ch)
+	}, logIdentity, logger)
+	return s
+}
+
+func (s *streamService) SubscribeHeads(ctx context.Context) (<-chan serializers.HeadResponse, *util.ErrorInfo) {
+	if s.heads == nil {
+		return nil, streamingDisabledError()
+	}
+	ch := s.heads.add(nil, nil)
+	return forward(ctx, s.heads, ch, headResponse), nil
+}
+
+func (s *streamService) SubscribeLogs(ctx context.Context, request serializers.StreamLogsRequest) (<-chan serializers.LogResponse, *util.ErrorInfo) {
+	if s.logs == nil {
+		return nil, streamingDisabledError()
+	}
+	query := filterQuery(request.LogFilter)
+	ch := s.logs.add(func(log types.Log) bool {
+		return matchLog(query, log)
+	}, &query)
+	return forward(ctx, s.logs, ch, s.decoder.logResponse), nil
+}
+
+func streamingDisabledError() *util.ErrorInfo {
+	return &util.ErrorInfo{
+		HttpCode: http.StatusServiceUnavailable,
+		Message:  util.StreamingDisabledErrorMessage,
+		Err:      errors.New("ETHEREUM_WS_URL is not configured"),
+	}
+}
+
+func headResponse(header *types.Header) serializers.HeadResponse {
+	response := serializers.HeadResponse{
+		Number:     header.Number.Uint64(),
+		Hash:       header.Hash().Hex(),
+		ParentHash: header.ParentHash.Hex(),
+		Timestamp:  header.Time,
+		GasLimit:   header.GasLimit,
+		GasUsed:    header.GasUsed,
+	}
+	if header.BaseFee != nil {
+		response.BaseFeePerGas = header.BaseFee.String()
+	}
+	return response
+}
+
+// forward converts the items of a subscriber channel until ctx is done or the subscriber is
+// dropped, then detaches it from the broadcaster.
+func forward[T any, R any](ctx context.Context, b *broadcaster[T], ch chan T, convert func(T) R) <-chan R {
+	out := make(chan R)
+	go func() {
+		defer close(out)
+		defer b.remove(ch)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case item, ok := <-ch:
+				if !ok {
+					return
+				}
+				select {
+				case out <- convert(item):
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return out
+}
+
+// subscribeFunc opens the upstream subscription. query is the union of the subscribers'
+// queries, streams without queries ignore it.
+type subscribeFunc[T any] func(ctx context.Context, client *ethclient.Client, query ethereum.FilterQuery, ch chan<- T) (ethereum.Subscription, error)
+
+type subscriber[T any] struct {
+	filter func(T) bool
+	query  *ethereum.FilterQuery
+}
+
+// broadcaster shares a single upstream subscription between all of its subscribers. The
+// upstream is opened with the first subscriber, closed after the last one leaves and
+// re-established with backoff whenever the connection fails. When the subscribers' queries
+// change it is replaced without a gap, using identity to skip items delivered twice.
+type broadcaster[T any] struct {
+	name      string
+	dial      DialFunc
+	subscribe subscribeFunc[T]
+	identity  func(T) any
+	logger    *logging.LogWrapper
+
+	mu          sync.Mutex
+	subscribers map[chan T]subscriber[T]
+	upstream    ethereum.FilterQuery
+	stop        context.CancelFunc
+	done        chan struct{} // closed once the latest run has exited
+	refresh     chan struct{}
+}
+
+func newBroadcaster[T any](name string, dial DialFunc, subscribe subscribeFunc[T], identity func(T) any, logger *logging.LogWrapper) *broadcaster[T] {
+	return &broadcaster[T]{
+		name:        name,
+		dial:        dial,
+		subscribe:   subscribe,
+		identity:    identity,
+		logger:      logger,
+		subscribers: make(map[chan T]subscriber[T]),
+		refresh:     make(chan struct{}, 1),
+	}
+}
+
+// add registers a subscriber that receives the items accepted by filter, or all items when
+// filter is nil. query narrows the upstream subscription to what the subscriber needs.
+func (b *broadcaster[T]) add(filter func(T) bool, query *ethereum.FilterQuery) chan T {
+	ch := make(chan T, streamBufferSize)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.subscribers[ch] = subscriber[T]{filter: filter, query: query}
+	if b.stop == nil {
+		ctx, cancel := context.WithCancel(context.Background())
+		b.stop = cancel
+		// A stopped run may still be unwinding, the new one waits for it so they never
+		// publish or consume refresh signals side by side.
+		previous, done := b.done, make(chan struct{})
+		b.done = done
+		go func() {
+			defer close(done)
+			if previous != nil {
+				<-previous
+			}
+			b.run(ctx)
+		}()
+	} else {
+		b.checkQuery()
+	}
+	return ch
+}
+
+func (b *broadcaster[T]) remove(ch chan T) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.drop(ch)
+}
+
+// drop must be called with mu held.
+func (b *broadcaster[T]) drop(ch chan T) {
+	_, ok := b.subscribers[ch]
+	if !ok {
+		return
+	}
+	delete(b.subscribers, ch)
+	close(ch)
+	if len(b.subscribers) == 0 && b.stop != nil {
+		b.stop()
+		b.stop = nil
+		return
+	}
+	b.checkQuery()
+}
+
+// checkQuery asks the stream to resubscribe when the subscribers no longer need the current
+// upstream query. It must be called with mu held.
+func (b *broadcaster[T]) checkQuery() {
+	if reflect.DeepEqual(b.query(), b.upstream) {
+		return
+	}
+	select {
+	case b.refresh <- struct{}{}:
+	default:
+	}
+}
+
+// query must be called with mu held.
+func (b *broadcaster[T]) query() ethereum.FilterQuery {
+	var queries []ethereum.FilterQuery
+	for _, sub := range b.subscribers {
+		if sub.query != nil {
+			queries = append(queries, *sub.query)
+		}
+	}
+	return unionFilterQuery(queries)
+}
+
+func (b *broadcaster[T]) nextUpstreamQuery() ethereum.FilterQuery {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.upstream = b.query()
+	return b.upstream
+}
+
+func (b *broadcaster[T]) publish(item T) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for ch, sub := range b.subscribers {
+		if sub.filter != nil && !sub.filter(item) {
+			continue
+		}
+		select {
+		case ch <- item:
+		default:
+			// A subscriber that cannot keep up is disconnected instead of stalling the others.
+			b.logger.Warn("Dropping slow stream subscriber", zap.String("stream", b.name))
+			b.drop(ch)
+		}
+	}
+}
+
+func (b *broadcaster[T]) run(ctx context.Context) {
+	// A refresh left over from the previous run is covered by the query subscribed below.
+	select {
+	case <-b.refresh:
+	default:
+	}
+	backoff := streamMinBackoff
+	for {
+		established, err := b.stream(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		if established {
+			backoff = streamMinBackoff
+		}
+		b.logger.Warn("Upstream subscription lost, reconnecting", zap.String("stream", b.name), zap.Error(err), zap.Duration("backoff", backoff))
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, streamMaxBackoff)
+	}
+}
+
+// stream dials the node and publishes items until the subscription fails or ctx is done.
+func (b *broadcaster[T]) stream(ctx context.Context) (bool, error) {
+	client, err := b.dial(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer client.Close()
+
+	ch := make(chan T, streamBufferSize)
+	sub, err := b.subscribe(ctx, client, b.nextUpstreamQuery(), ch)
+	if err != nil {
+		return false, err
+	}
+	b.logger.Info("Upstream subscription established", zap.String("stream", b.name))
+
+	// A replaced subscription keeps running for streamOverlap next to its replacement, so
+	// nothing is missed in between. Items both deliver are published once: seen collects the
+	// items of the overlap and is kept afterwards for copies the replacement still has queued.
+	var (
+		prevSub   ethereum.Subscription
+		prevCh    chan T
+		prevErr   <-chan error
+		overlap   <-chan time.Time
+		refresh   = b.refresh
+		seen      map[any]struct{}
+		recording bool
+	)
+	defer func() {
+		sub.Unsubscribe()
+		if prevSub != nil {
+			prevSub.Unsubscribe()
+		}
+	}()
+	publish := func(item T) {
+		if seen != nil {
+			key := b.identity(item)
+			if _, ok := seen[key]; ok {
+				return
+			}
+			if recording {
+				seen[key] = struct{}{}
+			}
+		}
+		b.publish(item)
+	}
+	endOverlap := func() {
+		prevSub.Unsubscribe()
+		for drained := false; !drained; {
+			select {
+			case item := <-prevCh:
+				publish(item)
+			default:
+				drained = true
+			}
+		}
+		prevSub, prevCh, prevErr, overlap, recording = nil, nil, nil, nil, false
+		refresh = b.refresh
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return true, ctx.Err()
+		case err := <-sub.Err():
+			if err == nil {
+				err = errors.New("subscription closed")
+			}
+			return true, err
+		case item := <-ch:
+			publish(item)
+		case item := <-prevCh:
+			publish(item)
+		case <-prevErr:
+			endOverlap()
+		case <-overlap:
+			endOverlap()
+		case <-refresh:
+			next := make(chan T, streamBufferSize)
+			nextSub, err := b.subscribe(ctx, client, b.nextUpstreamQuery(), next)
+			if err != nil {
+				return true, err
+			}
+			prevSub, prevCh, prevErr = sub, ch, sub.Err()
+			sub, ch = nextSub, next
+			overlap = time.After(streamOverlap)
+			seen, recording = make(map[any]struct{}), true
+			// Further changes wait until this replacement is complete.
+			refresh = nil
+		}
+	}
+}
+
+// logIdentity tells apart the logs of a block, including their reorg removals.
+func logIdentity(log types.Log) any {
+	return struct {
+		blockHash common.Hash
+		index     uint
+		removed   bool
+	}{log.BlockHash, log.Index, log.Removed}
+}
+
+// unionFilterQuery returns a query matching every log any of queries matches. Topic positions
+// are combined independently, so it may match more, which the subscribers filter out.
+func unionFilterQuery(queries []ethereum.FilterQuery) ethereum.FilterQuery {
+	var union ethereum.FilterQuery
+	if len(queries) == 0 {
+		return union
+	}
+	var addresses []common.Address
+	anyAddress := false
+	positions := 0
+	for _, query := range queries {
+		if len(query.Addresses) == 0 {
+			anyAddress = true
+		}
+		for _, address := range query.Addresses {
+			if !slices.Contains(addresses, address) {
+				addresses = append(addresses, address)
+			}
+		}
+		positions = max(positions, len(query.Topics))
+	}
+	if !anyAddress {
+		slices.SortFunc(addresses, func(a, b common.Address) int { return a.Cmp(b) })
+		union.Addresses = addresses
+	}
+	for i := 0; i < positions; i++ {
+		var topics []common.Hash
+		for _, query := range queries {
+			if i >= len(query.Topics) || len(query.Topics[i]) == 0 {
+				topics = nil
+				break
+			}
+			for _, topic := range query.Topics[i] {
+				if !slices.Contains(topics, topic) {
+					topics = append(topics, topic)
+				}
+			}
+		}
+		slices.SortFunc(topics, func(a, b common.Hash) int { return a.Cmp(b) })
+		union.Topics = append(union.Topics, topics)
+	}
+	for len(union.Topics) > 0 && union.Topics[len(union.Topics)-1] == nil {
+		union.Topics = union.Topics[:len(union.Topics)-1]
+	}
+	return union
+}
@@ -0,0 +1,132 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"go.uber.org/zap"
+	"golang-ethereum-example-api/pkg/contracts"
+	"golang-ethereum-example-api/pkg/logging"
+	"golang-ethereum-example-api/pkg/util"
+	"golang.org/x/sync/singleflight"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+type TokenMetadata struct {
+	Symbol   string
+	Decimals uint8
+}
+
+// TokenMetadataCache remembers the symbol and decimals of ERC-20 contracts, which never change.
+type TokenMetadataCache interface {
+	Get(ctx context.Context, token common.Address) (*TokenMetadata, error)
+}
+
+type tokenMetadataCache struct {
+	client *ethclient.Client
+	logger *logging.LogWrapper
+
+	mu     sync.RWMutex
+	tokens map[common.Address]*TokenMetadata
+	// lookups shares one in-progress lookup between concurrent requests for the same token.
+	lookups singleflight.Group
+}
+
+func NewTokenMetadataCache(client *ethclient.Client, logger *logging.LogWrapper) TokenMetadataCache {
+	return &tokenMetadataCache{
+		client: client,
+		logger: logger,
+		tokens: make(map[common.Address]*TokenMetadata),
+	}
+}
+
+func (c *tokenMetadataCache) Get(ctx context.Context, token common.Address) (*TokenMetadata, error) {
+	c.mu.RLock()
+	metadata, ok := c.tokens[token]
+	c.mu.RUnlock()
+	if ok {
+		return metadata, nil
+	}
+	result, err, _ := c.lookups.Do(token.Hex(), func() (interface{}, error) {
+		return c.lookup(ctx, token)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.(*TokenMetadata), nil
+}
+
+func (c *tokenMetadataCache) lookup(ctx context.Context, token common.Address) (*TokenMetadata, error) {
+	contract := contracts.NewERC20(token, c.client)
+	opts := &bind.CallOpts{Context: ctx}
+	var decimals []interface{}
+	if err := contract.Call(opts, &decimals, "decimals"); err != nil {
+		return nil, err
+	}
+	metadata := &TokenMetadata{Decimals: decimals[0].(uint8)}
+
+	// symbol is optional in ERC-20 and some older tokens return bytes32, so it is best effort.
+	// Only a token that really lacks a string symbol is cached without one, a failed request
+	// is retried by the next lookup.
+	var symbol []interface{}
+	if err := contract.Call(opts, &symbol, "symbol"); err != nil {
+		c.logger.Warn("TokenMetadata getting symbol error", zap.Error(err), zap.String("token", token.Hex()))
+		if !isRevert(err) && !isUnpackError(err) {
+			return metadata, nil
+		}
+	} else {
+		metadata.Symbol = symbol[0].(string)
+	}
+
+	c.mu.Lock()
+	c.tokens[token] = metadata
+	c.mu.Unlock()
+	return metadata, nil
+}
+
+// callTokenUint calls an ERC-20 view method returning a single uint256.
+func callTokenUint(opts *bind.CallOpts, client *ethclient.Client, token common.Address, method string, args ...interface{}) (*big.Int, error) {
+	var out []interface{}
+	err := contracts.NewERC20(token, client).Call(opts, &out, method, args...)
+	if err != nil {
+		return nil, err
+	}
+	return out[0].(*big.Int), nil
+}
+
+// tokenCallError maps a failed contract call to a client error when the address is not a
+// contract or does not implement the called method, and to an internal error otherwise.
+func tokenCallError(logger *logging.LogWrapper, msg string, token common.Address, err error) *util.ErrorInfo {
+	var rpcErr rpc.Error
+	if errors.As(err, &rpcErr) {
+		return &util.ErrorInfo{
+			HttpCode: http.StatusBadRequest,
+			Message:  util.InvalidTokenErrorMessage,
+			Detail:   revertReason(err),
+			Err:      err,
+		}
+	}
+	if errors.Is(err, bind.ErrNoCode) || isUnpackError(err) {
+		return &util.ErrorInfo{
+			HttpCode: http.StatusBadRequest,
+			Message:  util.InvalidTokenErrorMessage,
+			Err:      err,
+		}
+	}
+	logger.Error(msg, zap.Error(err), zap.String("token", token.Hex()))
+	return &util.ErrorInfo{
+		HttpCode: http.StatusInternalServerError,
+		Message:  util.InternalServiceErrorMessage,
+		Err:      err,
+	}
+}
+
+func isUnpackError(err error) bool {
+	return strings.HasPrefix(err.Error(), "abi: ")
+}
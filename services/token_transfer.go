@@ -0,0 +1,232 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/core/types"
+	"golang-ethereum-example-api/pkg/contracts"
+	"golang-ethereum-example-api/pkg/util"
+	"golang-ethereum-example-api/serializers"
+	"math/big"
+	"net/http"
+)
+
+func (s *transferService) SendToken(ctx context.Context, request serializers.SendTokenRequest) (*serializers.SendTokenResponse, *util.ErrorInfo) {
+	fromAccount := common.HexToAddress(request.FromAddress)
+	toAccount := common.HexToAddress(request.ToAddress)
+	token := common.HexToAddress(request.TokenAddress)
+	metadata, err := s.tokens.Get(ctx, token)
+	if err != nil {
+		return nil, tokenCallError(s.logger, "SendToken getting token metadata error", token, err)
+	}
+	amount, err := util.ParseUnits(request.Amount, int(metadata.Decimals))
+	if err == nil && amount.Cmp(math.MaxBig256) > 0 {
+		err = errors.New("amount does not fit in uint256")
+	}
+	if err != nil {
+		return nil, &util.ErrorInfo{
+			HttpCode: http.StatusBadRequest,
+			Message:  util.InvalidAmountErrorMessage,
+			Err:      err,
+		}
+	}
+
+	balance, err := callTokenUint(&bind.CallOpts{Context: ctx, Pending: true}, s.client, token, "balanceOf", fromAccount)
+	if err != nil {
+		return nil, tokenCallError(s.logger, "SendToken getting balance error", token, err)
+	}
+	if balance.Cmp(amount) < 0 {
+		return nil, &util.ErrorInfo{
+			HttpCode: http.StatusBadRequest,
+			Message:  util.InsufficientBalanceErrorMessage,
+			Detail:   fmt.Sprintf("token balance is %s", util.FormatUnits(balance, int(metadata.Decimals))),
+			Err:      errors.New("insufficient token balance"),
+		}
+	}
+
+	data, err := contracts.ERC20.Pack("transfer", toAccount, amount)
+	if err != nil {
+		return nil, &util.ErrorInfo{
+			HttpCode: http.StatusInternalServerError,
+			Message:  util.InternalServiceErrorMessage,
+			Err:      err,
+		}
+	}
+	errInfo := s.simulateTokenCall(ctx, fromAccount, token, "transfer", data)
+	if errInfo != nil {
+		return nil, errInfo
+	}
+
+	signedTx, fees, errInfo := s.sendTransaction(ctx, txRequest{
+		kind:                 transferKindToken,
+		request:              request,
+		from:                 fromAccount,
+		privateKey:           request.PrivateKey,
+		to:                   &token,
+		value:                new(big.Int),
+		data:                 data,
+		maxFeePerGas:         request.MaxFeePerGas,
+		maxPriorityFeePerGas: request.MaxPriorityFeePerGas,
+		gasLimit:             request.GasLimit,
+	})
+	if errInfo != nil {
+		return nil, errInfo
+	}
+	response := &serializers.SendTokenResponse{
+		TransactionHash: signedTx.Hash().Hex(),
+		TokenAddress:    request.TokenAddress,
+		Symbol:          metadata.Symbol,
+		Amount:          amount.String(),
+		Value:           util.FormatUnits(amount, int(metadata.Decimals)),
+		GasLimit:        signedTx.Gas(),
+		TransactionFees: fees.serialize(),
+	}
+	response.Receipt, errInfo = s.awaitReceipt(ctx, signedTx, request.WaitConfirmations, request.TimeoutSeconds)
+	if errInfo != nil {
+		return nil, errInfo
+	}
+	return response, nil
+}
+
+func (s *transferService) ApproveToken(ctx context.Context, request serializers.ApproveTokenRequest) (*serializers.ApproveTokenResponse, *util.ErrorInfo) {
+	fromAccount := common.HexToAddress(request.FromAddress)
+	token := common.HexToAddress(request.TokenAddress)
+	spender := common.HexToAddress(request.SpenderAddress)
+	metadata, err := s.tokens.Get(ctx, token)
+	if err != nil {
+		return nil, tokenCallError(s.logger, "ApproveToken getting token metadata error", token, err)
+	}
+	amount := new(big.Int).Set(math.MaxBig256)
+	if !request.Unlimited {
+		amount, err = util.ParseUnits(request.Amount, int(metadata.Decimals))
+		if err == nil && amount.Cmp(math.MaxBig256) > 0 {
+			err = errors.New("amount does not fit in uint256")
+		}
+		if err != nil {
+			return nil, &util.ErrorInfo{
+				HttpCode: http.StatusBadRequest,
+				Message:  util.InvalidAmountErrorMessage,
+				Err:      err,
+			}
+		}
+	}
+	response := &serializers.ApproveTokenResponse{
+		TokenAddress:   request.TokenAddress,
+		SpenderAddress: request.SpenderAddress,
+		Symbol:         metadata.Symbol,
+		Amount:         amount.String(),
+		Value:          util.FormatUnits(amount, int(metadata.Decimals)),
+		Unlimited:      request.Unlimited,
+	}
+
+	// Both the reset and the approve are waited for within the one timeout of the request.
+	waitCtx, cancel := context.WithTimeout(ctx, s.confirmationTimeout(request.TimeoutSeconds))
+	defer cancel()
+
+	// Tokens like USDT refuse to change a non-zero allowance to another non-zero value, so safe
+	// mode resets it to zero first and waits for that to be mined.
+	if request.SafeApprove && amount.Sign() > 0 {
+		allowance, err := callTokenUint(&bind.CallOpts{Context: ctx, Pending: true}, s.client, token, "allowance", fromAccount, spender)
+		if err != nil {
+			return nil, tokenCallError(s.logger, "ApproveToken getting allowance error", token, err)
+		}
+		if allowance.Sign() > 0 {
+			resetTx, _, errInfo := s.sendApprove(ctx, request, token, spender, new(big.Int))
+			if errInfo != nil {
+				return nil, errInfo
+			}
+			response.ResetTransactionHash = resetTx.Hash().Hex()
+			resetReceipt, errInfo := s.awaitReceipt(waitCtx, resetTx, 1, request.TimeoutSeconds)
+			if errInfo != nil {
+				return nil, errInfo
+			}
+			// The approve is only simulated and priced once the reset is mined, so it cannot be
+			// queued behind a reset that is still pending.
+			if resetReceipt == nil {
+				return nil, &util.ErrorInfo{
+					HttpCode: http.StatusGatewayTimeout,
+					Message:  util.ApproveNotSentErrorMessage,
+					Detail:   fmt.Sprintf("allowance reset %s was not mined in time, retry the approve once it is", resetTx.Hash().Hex()),
+					Err:      errors.New("allowance reset not mined"),
+				}
+			}
+			if resetReceipt.Status != transactionStatusMined {
+				return nil, &util.ErrorInfo{
+					HttpCode: http.StatusBadRequest,
+					Message:  util.ExecutionRevertedErrorMessage,
+					Detail:   fmt.Sprintf("resetting the allowance to zero failed in %s", resetTx.Hash().Hex()),
+					Err:      errors.New("allowance reset transaction failed"),
+				}
+			}
+		}
+	}
+
+	signedTx, fees, errInfo := s.sendApprove(ctx, request, token, spender, amount)
+	if errInfo != nil {
+		return nil, errInfo
+	}
+	response.TransactionHash = signedTx.Hash().Hex()
+	response.GasLimit = signedTx.Gas()
+	response.TransactionFees = fees.serialize()
+	response.Receipt, errInfo = s.awaitReceipt(waitCtx, signedTx, request.WaitConfirmations, request.TimeoutSeconds)
+	if errInfo != nil {
+		return nil, errInfo
+	}
+	return response, nil
+}
+
+func (s *transferService) sendApprove(ctx context.Context, request serializers.ApproveTokenRequest, token common.Address, spender common.Address, amount *big.Int) (*types.Transaction, *txFees, *util.ErrorInfo) {
+	fromAccount := common.HexToAddress(request.FromAddress)
+	data, err := contracts.ERC20.Pack("approve", spender, amount)
+	if err != nil {
+		return nil, nil, &util.ErrorInfo{
+			HttpCode: http.StatusInternalServerError,
+			Message:  util.InternalServiceErrorMessage,
+			Err:      err,
+		}
+	}
+	errInfo := s.simulateTokenCall(ctx, fromAccount, token, "approve", data)
+	if errInfo != nil {
+		return nil, nil, errInfo
+	}
+	return s.sendTransaction(ctx, txRequest{
+		kind:                 transferKindApprove,
+		request:              request,
+		from:                 fromAccount,
+		privateKey:           request.PrivateKey,
+		to:                   &token,
+		value:                new(big.Int),
+		data:                 data,
+		maxFeePerGas:         request.MaxFeePerGas,
+		maxPriorityFeePerGas: request.MaxPriorityFeePerGas,
+		gasLimit:             request.GasLimit,
+	})
+}
+
+// simulateTokenCall dry runs an ERC-20 state changing call. Besides reverting, tokens may
+// signal failure by returning false, which would otherwise be mined as a successful no-op.
+func (s *transferService) simulateTokenCall(ctx context.Context, from common.Address, token common.Address, method string, data []byte) *util.ErrorInfo {
+	output, errInfo := s.simulateCall(ctx, ethereum.CallMsg{From: from, To: &token, Data: data})
+	if errInfo != nil {
+		return errInfo
+	}
+	// Tokens such as USDT return nothing instead of a bool.
+	if len(output) == 0 {
+		return nil
+	}
+	result, err := contracts.ERC20.Unpack(method, output)
+	if err == nil && !result[0].(bool) {
+		return &util.ErrorInfo{
+			HttpCode: http.StatusBadRequest,
+			Message:  util.ExecutionRevertedErrorMessage,
+			Detail:   fmt.Sprintf("token %s returned false", method),
+			Err:      errors.New("token call returned false"),
+		}
+	}
+	return nil
+}
@@ -3,13 +3,16 @@ package services
 import (
 	"context"
 	"errors"
+	"fmt"
+	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
-	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
 	"go.uber.org/zap"
 	"golang-ethereum-example-api/pkg/logging"
 	"golang-ethereum-example-api/pkg/settings"
+	"golang-ethereum-example-api/pkg/signer"
 	"golang-ethereum-example-api/pkg/util"
 	"golang-ethereum-example-api/serializers"
 	"math/big"
@@ -18,54 +21,257 @@ import (
 
 type TransferService interface {
 	SendEthereum(ctx context.Context, request serializers.SendEthereumRequest) (*serializers.SendEthereumResponse, *util.ErrorInfo)
+	SendToken(ctx context.Context, request serializers.SendTokenRequest) (*serializers.SendTokenResponse, *util.ErrorInfo)
+	ApproveToken(ctx context.Context, request serializers.ApproveTokenRequest) (*serializers.ApproveTokenResponse, *util.ErrorInfo)
+	TransferErc721(ctx context.Context, request serializers.TransferErc721Request) (*serializers.TransferNftResponse, *util.ErrorInfo)
+	TransferErc1155(ctx context.Context, request serializers.TransferErc1155Request) (*serializers.TransferNftResponse, *util.ErrorInfo)
+	TransferErc1155Batch(ctx context.Context, request serializers.TransferErc1155BatchRequest) (*serializers.TransferNftResponse, *util.ErrorInfo)
+	EstimateTransfer(ctx context.Context, request serializers.EstimateTransferRequest) (*serializers.EstimateTransferResponse, *util.ErrorInfo)
+	GetTransaction(ctx context.Context, request serializers.GetTransactionRequest) (*serializers.GetTransactionResponse, *util.ErrorInfo)
+	PrepareTransfer(ctx context.Context, request serializers.PrepareTransferRequest) (*serializers.PrepareTransferResponse, *util.ErrorInfo)
+	SendRawTransaction(ctx context.Context, request serializers.SendRawTransactionRequest) (*serializers.SendRawTransactionResponse, *util.ErrorInfo)
+	SpeedUpTransaction(ctx context.Context, request serializers.ReplaceTransactionRequest) (*serializers.ReplaceTransactionResponse, *util.ErrorInfo)
+	CancelTransaction(ctx context.Context, request serializers.ReplaceTransactionRequest) (*serializers.ReplaceTransactionResponse, *util.ErrorInfo)
+	ListTransfers(ctx context.Context, request serializers.ListTransfersRequest) (*serializers.ListTransfersResponse, *util.ErrorInfo)
 }
 
 type transferService struct {
-	client *ethclient.Client
-	config *settings.EthereumClient
-	logger *logging.LogWrapper
+	client       *ethclient.Client
+	config       *settings.EthereumClient
+	serverConfig *settings.Server
+	walletConfig *settings.Wallet
+	signers      signer.Provider
+	nonces       NonceManager
+	tokens       TokenMetadataCache
+	ledger       TransferLedger
+	chainID      *big.Int
+	logger       *logging.LogWrapper
 }
 
-func NewTransferService(client *ethclient.Client, config *settings.EthereumClient, logger *logging.LogWrapper) TransferService {
-	return &transferService{client: client, config: config, logger: logger}
+func NewTransferService(client *ethclient.Client, config *settings.EthereumClient, serverConfig *settings.Server, walletConfig *settings.Wallet, signers signer.Provider, nonces NonceManager, tokens TokenMetadataCache, ledger TransferLedger, chainID *big.Int, logger *logging.LogWrapper) TransferService {
+	return &transferService{
+		client:       client,
+		config:       config,
+		serverConfig: serverConfig,
+		walletConfig: walletConfig,
+		signers:      signers,
+		nonces:       nonces,
+		tokens:       tokens,
+		ledger:       ledger,
+		chainID:      chainID,
+		logger:       logger,
+	}
 }
 
 func (s *transferService) SendEthereum(ctx context.Context, request serializers.SendEthereumRequest) (*serializers.SendEthereumResponse, *util.ErrorInfo) {
 	fromAccount := common.HexToAddress(request.FromAddress)
 	toAccount := common.HexToAddress(request.ToAddress)
-	privateKey, err := crypto.HexToECDSA(request.PrivateKey[2:])
+	amount, err := util.ParseAmount(request.EthereumAmount, request.Unit)
 	if err != nil {
-		s.logger.Error("TransferEthereum privateKey to ECDSA error", zap.Error(err))
 		return nil, &util.ErrorInfo{
+			HttpCode: http.StatusBadRequest,
+			Message:  util.InvalidAmountErrorMessage,
+			Err:      err,
+		}
+	}
+
+	signedTx, fees, errInfo := s.sendTransaction(ctx, txRequest{
+		kind:                 transferKindEther,
+		request:              request,
+		from:                 fromAccount,
+		privateKey:           request.PrivateKey,
+		to:                   &toAccount,
+		value:                amount,
+		maxFeePerGas:         request.MaxFeePerGas,
+		maxPriorityFeePerGas: request.MaxPriorityFeePerGas,
+		gasLimit:             request.GasLimit,
+	})
+	if errInfo != nil {
+		return nil, errInfo
+	}
+	response := &serializers.SendEthereumResponse{
+		TransactionHash: signedTx.Hash().Hex(),
+		Value:           amount.String(),
+		GasLimit:        signedTx.Gas(),
+		TransactionFees: fees.serialize(),
+	}
+	response.Receipt, errInfo = s.awaitReceipt(ctx, signedTx, request.WaitConfirmations, request.TimeoutSeconds)
+	if errInfo != nil {
+		return nil, errInfo
+	}
+	return response, nil
+}
+
+// txRequest describes a transaction to sign and broadcast on behalf of from. Empty fee fields
+// and a zero gas limit are filled in from the node. kind and request are stored in the ledger.
+type txRequest struct {
+	kind                 string
+	request              interface{}
+	from                 common.Address
+	privateKey           string
+	to                   *common.Address
+	value                *big.Int
+	data                 []byte
+	maxFeePerGas         string
+	maxPriorityFeePerGas string
+	gasLimit             uint64
+}
+
+// sendTransaction is the single path every state changing endpoint uses to price, sign and
+// broadcast a transaction, so they all share the signer, nonce and gas handling.
+func (s *transferService) sendTransaction(ctx context.Context, request txRequest) (*types.Transaction, *txFees, *util.ErrorInfo) {
+	txSigner, errInfo := s.resolveSigner(ctx, request.from, request.privateKey)
+	if errInfo != nil {
+		return nil, nil, errInfo
+	}
+
+	fees, errInfo := s.suggestFees(ctx, request.maxFeePerGas, request.maxPriorityFeePerGas)
+	if errInfo != nil {
+		return nil, nil, errInfo
+	}
+
+	gasLimit, errInfo := s.estimateGas(ctx, fees.callMsg(request.from, request.to, request.value, request.data), request.gasLimit)
+	if errInfo != nil {
+		return nil, nil, errInfo
+	}
+
+	nonceLease, err := s.nonces.Acquire(ctx, request.from)
+	if err != nil {
+		s.logger.Error("SendTransaction getting nonce error", zap.Error(err))
+		return nil, nil, &util.ErrorInfo{
 			HttpCode: http.StatusInternalServerError,
 			Message:  util.InternalServiceErrorMessage,
 			Err:      err,
 		}
 	}
-	nonce, err := s.client.PendingNonceAt(ctx, fromAccount)
+
+	tx := fees.newTx(s.chainID, nonceLease.Nonce, request.to, request.value, gasLimit, request.data)
+	signedTx, err := txSigner.SignTx(ctx, tx, s.chainID)
 	if err != nil {
-		s.logger.Error("TransferEthereum getting nonce error", zap.Error(err))
-		return nil, &util.ErrorInfo{
+		nonceLease.Release(nil)
+		s.logger.Error("SendTransaction sign transaction error", zap.Error(err))
+		return nil, nil, &util.ErrorInfo{
 			HttpCode: http.StatusInternalServerError,
 			Message:  util.InternalServiceErrorMessage,
 			Err:      err,
 		}
 	}
-	amount, err := etherToWei(request.EthereumAmount)
+
+	errInfo = s.broadcast(ctx, request, signedTx)
+	if errInfo != nil {
+		nonceLease.Release(errInfo.Err)
+		return nil, nil, errInfo
+	}
+	nonceLease.Commit()
+	return signedTx, fees, nil
+}
+
+// broadcast hands a signed transaction to the node and records the attempt in the ledger.
+func (s *transferService) broadcast(ctx context.Context, request txRequest, signedTx *types.Transaction) *util.ErrorInfo {
+	id := s.recordSubmission(request, signedTx)
+	err := s.client.SendTransaction(ctx, signedTx)
+	s.recordBroadcast(id, signedTx, err)
 	if err != nil {
-		s.logger.Error("TransferEthereum etherToWei error", zap.Error(err))
-		return nil, &util.ErrorInfo{
+		s.logger.Warn("SendTransaction send transaction error", zap.Error(err), zap.String("hash", signedTx.Hash().Hex()))
+		return &util.ErrorInfo{
 			HttpCode: http.StatusInternalServerError,
 			Message:  util.InternalServiceErrorMessage,
 			Err:      err,
 		}
 	}
+	return nil
+}
 
-	gasLimit := s.config.GasLimit
+func (s *transferService) EstimateTransfer(ctx context.Context, request serializers.EstimateTransferRequest) (*serializers.EstimateTransferResponse, *util.ErrorInfo) {
+	fromAccount := common.HexToAddress(request.FromAddress)
+	toAccount := common.HexToAddress(request.ToAddress)
+	amount, err := util.ParseAmount(request.EthereumAmount, request.Unit)
+	if err != nil {
+		return nil, &util.ErrorInfo{
+			HttpCode: http.StatusBadRequest,
+			Message:  util.InvalidAmountErrorMessage,
+			Err:      err,
+		}
+	}
+
+	fees, errInfo := s.suggestFees(ctx, request.MaxFeePerGas, request.MaxPriorityFeePerGas)
+	if errInfo != nil {
+		return nil, errInfo
+	}
+
+	gasLimit, errInfo := s.estimateGas(ctx, fees.callMsg(fromAccount, &toAccount, amount, nil), request.GasLimit)
+	if errInfo != nil {
+		return nil, errInfo
+	}
+
+	maxCost := new(big.Int).Mul(fees.maxGasPrice(), new(big.Int).SetUint64(gasLimit))
+	maxCost.Add(maxCost, amount)
+	return &serializers.EstimateTransferResponse{
+		GasLimit:        gasLimit,
+		Value:           amount.String(),
+		MaxCost:         maxCost.String(),
+		TransactionFees: fees.serialize(),
+	}, nil
+}
 
-	gasPrice, err := s.client.SuggestGasPrice(ctx)
+// estimateGas adds the configured safety multiplier to the node's estimate and caps it at the
+// configured gas limit. A caller supplied gas limit skips the estimation.
+func (s *transferService) estimateGas(ctx context.Context, msg ethereum.CallMsg, gasLimitOverride uint64) (uint64, *util.ErrorInfo) {
+	if gasLimitOverride != 0 {
+		if gasLimitOverride > s.config.GasLimit {
+			return 0, &util.ErrorInfo{
+				HttpCode: http.StatusBadRequest,
+				Message:  util.InvalidGasLimitErrorMessage,
+				Err:      fmt.Errorf("gas limit is higher than the configured limit %d", s.config.GasLimit),
+			}
+		}
+		return gasLimitOverride, nil
+	}
+
+	estimated, err := s.client.EstimateGas(ctx, msg)
 	if err != nil {
-		s.logger.Error("TransferEthereum suggestGasPrice error", zap.Error(err))
+		var rpcErr rpc.Error
+		if errors.As(err, &rpcErr) {
+			return 0, &util.ErrorInfo{
+				HttpCode: http.StatusBadRequest,
+				Message:  util.GasEstimationErrorMessage,
+				Detail:   revertReason(err),
+				Err:      err,
+			}
+		}
+		s.logger.Error("TransferEthereum estimateGas error", zap.Error(err))
+		return 0, &util.ErrorInfo{
+			HttpCode: http.StatusInternalServerError,
+			Message:  util.InternalServiceErrorMessage,
+			Err:      err,
+		}
+	}
+	if estimated > s.config.GasLimit {
+		return 0, &util.ErrorInfo{
+			HttpCode: http.StatusBadRequest,
+			Message:  util.GasEstimationErrorMessage,
+			Err:      fmt.Errorf("estimated gas %d is higher than the configured limit %d", estimated, s.config.GasLimit),
+		}
+	}
+	gasLimit := uint64(float64(estimated) * s.config.GasMultiplier)
+	if gasLimit > s.config.GasLimit {
+		gasLimit = s.config.GasLimit
+	}
+	return gasLimit, nil
+}
+
+func (s *transferService) GetTransaction(ctx context.Context, request serializers.GetTransactionRequest) (*serializers.GetTransactionResponse, *util.ErrorInfo) {
+	hash := common.HexToHash(request.Hash)
+	tx, isPending, err := s.client.TransactionByHash(ctx, hash)
+	if errors.Is(err, ethereum.NotFound) {
+		return nil, &util.ErrorInfo{
+			HttpCode: http.StatusNotFound,
+			Message:  util.TransactionNotFoundErrorMessage,
+			Err:      err,
+		}
+	}
+	if err != nil {
+		s.logger.Error("GetTransaction getting transaction error", zap.Error(err), zap.String("hash", request.Hash))
 		return nil, &util.ErrorInfo{
 			HttpCode: http.StatusInternalServerError,
 			Message:  util.InternalServiceErrorMessage,
@@ -73,62 +279,281 @@ func (s *transferService) SendEthereum(ctx context.Context, request serializers.
 		}
 	}
 
-	txData := &types.LegacyTx{
-		Nonce:    nonce,
-		To:       &toAccount,
-		Value:    amount,
-		Gas:      gasLimit,
-		GasPrice: gasPrice,
-		Data:     nil,
+	response := s.transactionSummary(tx)
+	if isPending {
+		return response, nil
 	}
-	tx := types.NewTx(txData)
 
-	/*		During the development phase, I utilized Ganache.
-			I encountered an error with this chainId in Ganache.
-			The detailed information regarding the error can be found here: https://github.com/trufflesuite/ganache/issues/4367.
-			Therefore, I am currently obtaining the chainId statically due to this issue.
-	chainID, err := s.client.NetworkID(ctx)
+	receipt, err := s.client.TransactionReceipt(ctx, hash)
+	if errors.Is(err, ethereum.NotFound) {
+		// The transaction was just included and the node has not indexed its receipt yet.
+		return response, nil
+	}
 	if err != nil {
-		s.logger.Error("TransferEthereum getting chainID error", zap.Error(err))
+		s.logger.Error("GetTransaction getting receipt error", zap.Error(err), zap.String("hash", request.Hash))
 		return nil, &util.ErrorInfo{
 			HttpCode: http.StatusInternalServerError,
 			Message:  util.InternalServiceErrorMessage,
 			Err:      err,
 		}
 	}
-	*/
-	chainID := new(big.Int).SetInt64(int64(1337))
-
-	signedTx, err := types.SignTx(tx, types.NewEIP155Signer(chainID), privateKey)
+	latestBlock, err := s.client.BlockNumber(ctx)
 	if err != nil {
-		s.logger.Error("TransferEthereum sign transaction error", zap.Error(err))
+		s.logger.Error("GetTransaction getting block number error", zap.Error(err))
 		return nil, &util.ErrorInfo{
 			HttpCode: http.StatusInternalServerError,
 			Message:  util.InternalServiceErrorMessage,
 			Err:      err,
 		}
 	}
+	fillReceipt(response, receipt, tx, latestBlock)
+	return response, nil
+}
 
-	err = s.client.SendTransaction(ctx, signedTx)
+func (s *transferService) transactionSummary(tx *types.Transaction) *serializers.GetTransactionResponse {
+	response := &serializers.GetTransactionResponse{
+		TransactionHash: tx.Hash().Hex(),
+		Status:          transactionStatusPending,
+		TransactionType: transactionTypeName(tx.Type()),
+		Value:           tx.Value().String(),
+		Nonce:           tx.Nonce(),
+	}
+	from, err := types.Sender(types.LatestSignerForChainID(s.chainID), tx)
+	if err == nil {
+		response.From = from.Hex()
+	}
+	if tx.To() != nil {
+		response.To = tx.To().Hex()
+	}
+	return response
+}
+
+func fillReceipt(response *serializers.GetTransactionResponse, receipt *types.Receipt, tx *types.Transaction, latestBlock uint64) {
+	blockNumber := receipt.BlockNumber.Uint64()
+	response.BlockNumber = &blockNumber
+	response.BlockHash = receipt.BlockHash.Hex()
+	if latestBlock >= blockNumber {
+		response.Confirmations = latestBlock - blockNumber + 1
+	}
+	response.GasUsed = receipt.GasUsed
+	effectiveGasPrice := receipt.EffectiveGasPrice
+	if effectiveGasPrice == nil {
+		effectiveGasPrice = tx.GasPrice()
+	}
+	response.EffectiveGasPrice = effectiveGasPrice.String()
+	if receipt.Status == types.ReceiptStatusSuccessful {
+		response.Status = transactionStatusMined
+		if tx.To() == nil {
+			response.ContractAddress = receipt.ContractAddress.Hex()
+		}
+	} else {
+		response.Status = transactionStatusFailed
+	}
+}
+
+// resolveSigner returns a signer for the caller supplied private key in raw key mode,
+// otherwise the signer of the managed account.
+func (s *transferService) resolveSigner(ctx context.Context, from common.Address, privateKeyHex string) (signer.Signer, *util.ErrorInfo) {
+	if privateKeyHex != "" {
+		if !s.walletConfig.AllowRawPrivateKey {
+			return nil, &util.ErrorInfo{
+				HttpCode: http.StatusBadRequest,
+				Message:  util.RawPrivateKeyDisabledErrorMessage,
+				Err:      errors.New("raw private keys are disabled"),
+			}
+		}
+		rawSigner, err := signer.NewRawSigner(privateKeyHex)
+		if err != nil {
+			return nil, &util.ErrorInfo{
+				HttpCode: http.StatusBadRequest,
+				Message:  util.InvalidPrivateKeyErrorMessage,
+				Err:      err,
+			}
+		}
+		if rawSigner.Address() != from {
+			return nil, &util.ErrorInfo{
+				HttpCode: http.StatusBadRequest,
+				Message:  util.InvalidPrivateKeyErrorMessage,
+				Err:      errors.New("private key does not belong to the sender"),
+			}
+		}
+		return rawSigner, nil
+	}
+	txSigner, err := s.signers.Signer(ctx, from)
+	if errors.Is(err, signer.ErrUnknownAccount) {
+		return nil, &util.ErrorInfo{
+			HttpCode: http.StatusNotFound,
+			Message:  util.UnknownAccountErrorMessage,
+			Err:      err,
+		}
+	}
 	if err != nil {
-		s.logger.Warn("TransferEthereum send transaction error", zap.Error(err))
+		s.logger.Error("TransferEthereum resolving signer error", zap.Error(err))
 		return nil, &util.ErrorInfo{
 			HttpCode: http.StatusInternalServerError,
 			Message:  util.InternalServiceErrorMessage,
 			Err:      err,
 		}
 	}
-	response := &serializers.SendEthereumResponse{
-		TransactionHash: signedTx.Hash().Hex(),
+	return txSigner, nil
+}
+
+type txFees struct {
+	gasPrice  *big.Int
+	gasTipCap *big.Int
+	gasFeeCap *big.Int
+}
+
+func (f *txFees) isDynamic() bool {
+	return f.gasFeeCap != nil
+}
+
+func (f *txFees) newTx(chainID *big.Int, nonce uint64, to *common.Address, value *big.Int, gasLimit uint64, data []byte) *types.Transaction {
+	if f.isDynamic() {
+		return types.NewTx(&types.DynamicFeeTx{
+			ChainID:   chainID,
+			Nonce:     nonce,
+			GasTipCap: f.gasTipCap,
+			GasFeeCap: f.gasFeeCap,
+			Gas:       gasLimit,
+			To:        to,
+			Value:     value,
+			Data:      data,
+		})
 	}
-	return response, nil
+	return types.NewTx(&types.LegacyTx{
+		Nonce:    nonce,
+		To:       to,
+		Value:    value,
+		Gas:      gasLimit,
+		GasPrice: f.gasPrice,
+		Data:     data,
+	})
+}
+
+func (f *txFees) callMsg(from common.Address, to *common.Address, value *big.Int, data []byte) ethereum.CallMsg {
+	msg := ethereum.CallMsg{
+		From:  from,
+		To:    to,
+		Value: value,
+		Data:  data,
+	}
+	if f.isDynamic() {
+		msg.GasFeeCap = f.gasFeeCap
+		msg.GasTipCap = f.gasTipCap
+	} else {
+		msg.GasPrice = f.gasPrice
+	}
+	return msg
+}
+
+// maxGasPrice is the highest price per gas the transaction can be charged.
+func (f *txFees) maxGasPrice() *big.Int {
+	if f.isDynamic() {
+		return f.gasFeeCap
+	}
+	return f.gasPrice
+}
+
+func (f *txFees) serialize() serializers.TransactionFees {
+	if f.isDynamic() {
+		return serializers.TransactionFees{
+			TransactionType:      transactionTypeDynamicFee,
+			MaxFeePerGas:         f.gasFeeCap.String(),
+			MaxPriorityFeePerGas: f.gasTipCap.String(),
+		}
+	}
+	return serializers.TransactionFees{
+		TransactionType: transactionTypeLegacy,
+		GasPrice:        f.gasPrice.String(),
+	}
+}
+
+const (
+	transactionTypeLegacy     = "legacy"
+	transactionTypeAccessList = "accessList"
+	transactionTypeDynamicFee = "dynamicFee"
+	transactionTypeBlob       = "blob"
+)
+
+const (
+	transactionStatusPending = "pending"
+	transactionStatusMined   = "mined"
+	transactionStatusFailed  = "failed"
+)
+
+func transactionTypeName(txType uint8) string {
+	switch txType {
+	case types.LegacyTxType:
+		return transactionTypeLegacy
+	case types.AccessListTxType:
+		return transactionTypeAccessList
+	case types.DynamicFeeTxType:
+		return transactionTypeDynamicFee
+	case types.BlobTxType:
+		return transactionTypeBlob
+	}
+	return fmt.Sprintf("0x%x", txType)
 }
 
-func etherToWei(ether float64) (*big.Int, error) {
-	weiFloat := new(big.Float).SetFloat64(ether * 1e18)
-	wei, err := new(big.Int).SetString(weiFloat.Text('f', 0), 10)
-	if !err {
-		return nil, errors.New("ether to wei convert error")
+// suggestFees builds EIP-1559 fee caps from the latest base fee when the node supports it,
+// and falls back to a legacy gas price otherwise. Caller supplied caps take precedence.
+func (s *transferService) suggestFees(ctx context.Context, maxFeePerGas string, maxPriorityFeePerGas string) (*txFees, *util.ErrorInfo) {
+	header, err := s.client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		s.logger.Error("TransferEthereum getting latest header error", zap.Error(err))
+		return nil, &util.ErrorInfo{
+			HttpCode: http.StatusInternalServerError,
+			Message:  util.InternalServiceErrorMessage,
+			Err:      err,
+		}
+	}
+
+	if header.BaseFee == nil {
+		if maxFeePerGas != "" || maxPriorityFeePerGas != "" {
+			return nil, &util.ErrorInfo{
+				HttpCode: http.StatusBadRequest,
+				Message:  util.InvalidFeeErrorMessage,
+				Err:      errors.New("node does not support dynamic fee transactions"),
+			}
+		}
+		gasPrice, err := s.client.SuggestGasPrice(ctx)
+		if err != nil {
+			s.logger.Error("TransferEthereum suggestGasPrice error", zap.Error(err))
+			return nil, &util.ErrorInfo{
+				HttpCode: http.StatusInternalServerError,
+				Message:  util.InternalServiceErrorMessage,
+				Err:      err,
+			}
+		}
+		return &txFees{gasPrice: gasPrice}, nil
+	}
+
+	var gasTipCap *big.Int
+	if maxPriorityFeePerGas != "" {
+		gasTipCap, _ = new(big.Int).SetString(maxPriorityFeePerGas, 10)
+	} else {
+		gasTipCap, err = s.client.SuggestGasTipCap(ctx)
+		if err != nil {
+			s.logger.Error("TransferEthereum suggestGasTipCap error", zap.Error(err))
+			return nil, &util.ErrorInfo{
+				HttpCode: http.StatusInternalServerError,
+				Message:  util.InternalServiceErrorMessage,
+				Err:      err,
+			}
+		}
+	}
+
+	var gasFeeCap *big.Int
+	if maxFeePerGas != "" {
+		gasFeeCap, _ = new(big.Int).SetString(maxFeePerGas, 10)
+		// A suggested tip must never exceed an explicit fee cap.
+		if gasTipCap.Cmp(gasFeeCap) > 0 {
+			gasTipCap = new(big.Int).Set(gasFeeCap)
+		}
+	} else {
+		// Leave room for the base fee to double before the transaction becomes unexecutable.
+		gasFeeCap = new(big.Int).Add(new(big.Int).Mul(header.BaseFee, big.NewInt(2)), gasTipCap)
 	}
-	return wei, nil
+	return &txFees{gasTipCap: gasTipCap, gasFeeCap: gasFeeCap}, nil
 }
@@ -0,0 +1,169 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"go.uber.org/zap"
+	"golang-ethereum-example-api/pkg/util"
+	"golang-ethereum-example-api/serializers"
+	"net/http"
+	"strconv"
+)
+
+const (
+	transferKindEther            = "ether"
+	transferKindToken            = "token"
+	transferKindApprove          = "approve"
+	transferKindErc721           = "erc721"
+	transferKindErc1155          = "erc1155"
+	transferKindErc1155Batch     = "erc1155Batch"
+	transferKindContractTransact = "contractTransact"
+	transferKindContractDeploy   = "contractDeploy"
+	transferKindSpeedUp          = "speedUp"
+	transferKindCancel           = "cancel"
+	transferKindRaw              = "raw"
+
+	defaultTransfersLimit = 50
+)
+
+// recordSubmission stores a transaction about to be broadcast, so the ledger knows about it
+// even when the process stops before the node answers. A ledger failure is only logged and
+// does not hold back the broadcast, the returned id is zero then.
+func (s *transferService) recordSubmission(request txRequest, signedTx *types.Transaction) uint64 {
+	raw, err := signedTx.MarshalBinary()
+	if err != nil {
+		s.logger.Error("RecordTransfer encoding transaction error", zap.Error(err))
+		return 0
+	}
+	record := &TransferRecord{
+		Kind:           request.kind,
+		From:           request.from,
+		To:             request.to,
+		Value:          signedTx.Value().String(),
+		Nonce:          signedTx.Nonce(),
+		Hash:           signedTx.Hash(),
+		RawTransaction: raw,
+		Request:        redactRequest(request.request),
+		Status:         TransferStatusSubmitting,
+	}
+	err = s.ledger.Record(record, "")
+	if err != nil {
+		s.logger.Error("RecordTransfer saving transfer error", zap.Error(err), zap.String("hash", signedTx.Hash().Hex()))
+		return 0
+	}
+	return record.Id
+}
+
+// recordBroadcast stores the outcome of the broadcast of a submitted record.
+func (s *transferService) recordBroadcast(id uint64, signedTx *types.Transaction, sendErr error) {
+	if id == 0 {
+		return
+	}
+	status := TransferStatusPending
+	var detail string
+	if sendErr != nil {
+		detail = sendErr.Error()
+		// Only an answer from the node is a rejection. After a timeout or a broken connection
+		// the transaction may have been accepted, so it stays pending for the tracker to
+		// resolve as mined or dropped.
+		if isNodeRejection(sendErr) {
+			status = TransferStatusRejected
+		} else {
+			detail = "broadcast outcome unknown: " + detail
+		}
+	}
+	err := s.ledger.ResolveSubmission(id, status, detail)
+	if err != nil {
+		s.logger.Error("RecordTransfer updating transfer error", zap.Error(err), zap.String("hash", signedTx.Hash().Hex()))
+	}
+}
+
+// redactRequest encodes an API request for the ledger without its private key.
+func redactRequest(request interface{}) json.RawMessage {
+	if request == nil {
+		return nil
+	}
+	data, err := json.Marshal(request)
+	if err != nil {
+		return nil
+	}
+	var fields map[string]json.RawMessage
+	err = json.Unmarshal(data, &fields)
+	if err != nil {
+		return nil
+	}
+	delete(fields, "privateKey")
+	data, err = json.Marshal(fields)
+	if err != nil {
+		return nil
+	}
+	return data
+}
+
+func (s *transferService) ListTransfers(ctx context.Context, request serializers.ListTransfersRequest) (*serializers.ListTransfersResponse, *util.ErrorInfo) {
+	query := TransferQuery{
+		Status: request.Status,
+		Limit:  request.Limit,
+	}
+	if query.Limit == 0 {
+		query.Limit = defaultTransfersLimit
+	}
+	if request.Address != "" {
+		address := common.HexToAddress(request.Address)
+		query.Address = &address
+	}
+	if request.Cursor != "" {
+		query.Cursor, _ = strconv.ParseUint(request.Cursor, 10, 64)
+	}
+
+	records, next, err := s.ledger.List(query)
+	if err != nil {
+		s.logger.Error("ListTransfers loading transfers error", zap.Error(err))
+		return nil, &util.ErrorInfo{
+			HttpCode: http.StatusInternalServerError,
+			Message:  util.InternalServiceErrorMessage,
+			Err:      err,
+		}
+	}
+	response := &serializers.ListTransfersResponse{
+		Transfers: make([]serializers.TransferRecordResponse, len(records)),
+	}
+	for i, record := range records {
+		response.Transfers[i] = transferRecordResponse(record)
+	}
+	if next != 0 {
+		response.NextCursor = strconv.FormatUint(next, 10)
+	}
+	return response, nil
+}
+
+func transferRecordResponse(record *TransferRecord) serializers.TransferRecordResponse {
+	response := serializers.TransferRecordResponse{
+		Id:             record.Id,
+		Kind:           record.Kind,
+		From:           record.From.Hex(),
+		Value:          record.Value,
+		Nonce:          record.Nonce,
+		Hash:           record.Hash.Hex(),
+		RawTransaction: record.RawTransaction.String(),
+		Request:        record.Request,
+		Status:         record.Status,
+		BlockNumber:    record.BlockNumber,
+		History:        make([]serializers.TransferStatusChangeResponse, len(record.History)),
+		CreatedAt:      record.CreatedAt,
+		UpdatedAt:      record.UpdatedAt,
+	}
+	if record.To != nil {
+		response.To = record.To.Hex()
+	}
+	for i, change := range record.History {
+		response.History[i] = serializers.TransferStatusChangeResponse{
+			Status: change.Status,
+			Detail: change.Detail,
+			At:     change.At,
+		}
+	}
+	return response
+}
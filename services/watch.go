@@ -0,0 +1,485 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"go.uber.org/zap"
+	"golang-ethereum-example-api/pkg/contracts"
+	"golang-ethereum-example-api/pkg/logging"
+	"golang-ethereum-example-api/pkg/settings"
+	"golang-ethereum-example-api/pkg/util"
+	"golang-ethereum-example-api/serializers"
+	"math/big"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	watchStateFile = "watch.json"
+	// watchMaxBlocksPerScan bounds a single scan so a long outage is caught up gradually.
+	watchMaxBlocksPerScan = 100
+
+	watchEventNative = "native"
+	watchEventErc20  = "erc20"
+
+	// Native deposits are read from the value of the block's transactions, ether moved to a
+	// watched address by a contract's internal call is not visible there.
+	watchCoverageNote = "native deposits are detected from top-level transactions only, ether received through internal contract calls is not reported"
+)
+
+var erc20TransferTopic = contracts.ERC20.Events["Transfer"].ID
+
+type WatchService interface {
+	Worker
+	WatchAddress(ctx context.Context, request serializers.WatchAddressRequest) (*serializers.WatchResponse, *util.ErrorInfo)
+	UnwatchAddress(ctx context.Context, request serializers.UnwatchAddressRequest) (*serializers.WatchResponse, *util.ErrorInfo)
+	ListWatches(ctx context.Context) (*serializers.WatchListResponse, *util.ErrorInfo)
+}
+
+type watchEntry struct {
+	WebhookUrl string    `json:"webhookUrl,omitempty"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+// watchState is persisted after every change so the watchlist and the scan position survive
+// a restart. Outbox holds the webhooks of scanned blocks that are not delivered yet, keyed by
+// event id, and is sent again after a restart.
+type watchState struct {
+	LastScannedBlock *uint64                       `json:"lastScannedBlock,omitempty"`
+	Addresses        map[common.Address]watchEntry `json:"addresses"`
+	Outbox           map[string]webhookDelivery    `json:"outbox,omitempty"`
+}
+
+type watchService struct {
+	client   *ethclient.Client
+	config   *settings.Watch
+	tokens   TokenMetadataCache
+	chainID  *big.Int
+	webhooks *webhookSender
+	logger   *logging.LogWrapper
+
+	mu    sync.Mutex
+	state watchState
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func NewWatchService(client *ethclient.Client, config *settings.Watch, tokens TokenMetadataCache, chainID *big.Int, logger *logging.LogWrapper) (WatchService, error) {
+	s := &watchService{
+		client:  client,
+		config:  config,
+		tokens:  tokens,
+		chainID: chainID,
+		logger:  logger,
+		state:   watchState{Addresses: make(map[common.Address]watchEntry)},
+	}
+	s.webhooks = newWebhookSender(config.WebhookSecret, config.MaxRetries, s.completeDelivery, logger)
+	err := os.MkdirAll(config.DataDir, 0700)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(filepath.Join(config.DataDir, watchStateFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal(data, &s.state)
+	if err != nil {
+		return nil, err
+	}
+	if s.state.Addresses == nil {
+		s.state.Addresses = make(map[common.Address]watchEntry)
+	}
+	return s, nil
+}
+
+// completeDelivery removes a webhook from the outbox once it was delivered or given up.
+func (s *watchService) completeDelivery(delivery webhookDelivery) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.state.Outbox, delivery.Event.Id)
+	err := s.saveState()
+	if err != nil {
+		s.logger.Error("Watch scanner saving outbox error", zap.Error(err), zap.String("id", delivery.Event.Id))
+	}
+}
+
+func (s *watchService) WatchAddress(ctx context.Context, request serializers.WatchAddressRequest) (*serializers.WatchResponse, *util.ErrorInfo) {
+	if request.WebhookUrl == "" && s.config.WebhookUrl == "" {
+		return nil, &util.ErrorInfo{
+			HttpCode: http.StatusBadRequest,
+			Message:  util.MissingWebhookUrlErrorMessage,
+			Err:      errors.New("no webhookUrl given and WATCH_WEBHOOK_URL is not configured"),
+		}
+	}
+	address := common.HexToAddress(request.Address)
+	entry := watchEntry{WebhookUrl: request.WebhookUrl, CreatedAt: time.Now().UTC()}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	previous, ok := s.state.Addresses[address]
+	if ok {
+		entry.CreatedAt = previous.CreatedAt
+	}
+	s.state.Addresses[address] = entry
+	err := s.saveState()
+	if err != nil {
+		if ok {
+			s.state.Addresses[address] = previous
+		} else {
+			delete(s.state.Addresses, address)
+		}
+		s.logger.Error("WatchAddress saving watchlist error", zap.Error(err))
+		return nil, &util.ErrorInfo{
+			HttpCode: http.StatusInternalServerError,
+			Message:  util.InternalServiceErrorMessage,
+			Err:      err,
+		}
+	}
+	response := watchResponse(address, entry)
+	response.Note = watchCoverageNote
+	return response, nil
+}
+
+func (s *watchService) UnwatchAddress(ctx context.Context, request serializers.UnwatchAddressRequest) (*serializers.WatchResponse, *util.ErrorInfo) {
+	address := common.HexToAddress(request.Address)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entry, ok := s.state.Addresses[address]
+	if !ok {
+		return nil, &util.ErrorInfo{
+			HttpCode: http.StatusNotFound,
+			Message:  util.AddressNotWatchedErrorMessage,
+			Err:      fmt.Errorf("%s is not watched", address.Hex()),
+		}
+	}
+	delete(s.state.Addresses, address)
+	err := s.saveState()
+	if err != nil {
+		s.state.Addresses[address] = entry
+		s.logger.Error("UnwatchAddress saving watchlist error", zap.Error(err))
+		return nil, &util.ErrorInfo{
+			HttpCode: http.StatusInternalServerError,
+			Message:  util.InternalServiceErrorMessage,
+			Err:      err,
+		}
+	}
+	return watchResponse(address, entry), nil
+}
+
+func (s *watchService) ListWatches(ctx context.Context) (*serializers.WatchListResponse, *util.ErrorInfo) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	response := &serializers.WatchListResponse{
+		LastScannedBlock: s.state.LastScannedBlock,
+		Addresses:        make([]serializers.WatchResponse, 0, len(s.state.Addresses)),
+		Note:             watchCoverageNote,
+	}
+	for address, entry := range s.state.Addresses {
+		response.Addresses = append(response.Addresses, *watchResponse(address, entry))
+	}
+	sort.Slice(response.Addresses, func(i, j int) bool {
+		return response.Addresses[i].Address < response.Addresses[j].Address
+	})
+	return response, nil
+}
+
+func watchResponse(address common.Address, entry watchEntry) *serializers.WatchResponse {
+	return &serializers.WatchResponse{
+		Address:    address.Hex(),
+		WebhookUrl: entry.WebhookUrl,
+		CreatedAt:  entry.CreatedAt,
+	}
+}
+
+// saveState must be called with mu held.
+func (s *watchService) saveState() error {
+	data, err := json.MarshalIndent(s.state, "", "  ")
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(s.config.DataDir, watchStateFile)
+	tmpPath := path + ".tmp"
+	err = os.WriteFile(tmpPath, data, 0600)
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}
+
+func (s *watchService) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	s.done = make(chan struct{})
+	s.webhooks.start()
+	go func() {
+		defer close(s.done)
+		s.resumeOutbox(ctx)
+		ticker := time.NewTicker(s.config.PollInterval)
+		defer ticker.Stop()
+		for {
+			s.scan(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	s.logger.Info("Watch scanner started", zap.Uint64("confirmations", s.config.Confirmations))
+}
+
+func (s *watchService) Stop(ctx context.Context) error {
+	if s.cancel == nil {
+		return nil
+	}
+	s.cancel()
+	select {
+	case <-s.done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	return s.webhooks.stop(ctx)
+}
+
+// scan processes every block that has reached the confirmation depth since the last scan.
+// Without watched addresses it only moves the scan position forward, so adding an address
+// never triggers a backfill of old blocks.
+func (s *watchService) scan(ctx context.Context) {
+	latest, err := s.client.BlockNumber(ctx)
+	if err != nil {
+		if ctx.Err() == nil {
+			s.logger.Warn("Watch scanner getting block number error", zap.Error(err))
+		}
+		return
+	}
+	if latest+1 < s.config.Confirmations {
+		return
+	}
+	target := latest + 1 - s.config.Confirmations
+
+	s.mu.Lock()
+	lastScanned := s.state.LastScannedBlock
+	watched := make(map[common.Address]watchEntry, len(s.state.Addresses))
+	for address, entry := range s.state.Addresses {
+		watched[address] = entry
+	}
+	s.mu.Unlock()
+
+	if lastScanned == nil || len(watched) == 0 {
+		s.setLastScanned(target)
+		return
+	}
+	if *lastScanned >= target {
+		return
+	}
+	from := *lastScanned + 1
+	to := min(target, from+watchMaxBlocksPerScan-1)
+
+	logs, err := s.filterTransferLogs(ctx, from, to, watched)
+	if err != nil {
+		if ctx.Err() == nil {
+			s.logger.Warn("Watch scanner filter logs error", zap.Error(err), zap.Uint64("fromBlock", from), zap.Uint64("toBlock", to))
+		}
+		return
+	}
+	for number := from; number <= to; number++ {
+		deliveries, err := s.scanBlock(ctx, number, latest, watched, logs[number])
+		if err != nil {
+			if ctx.Err() == nil {
+				s.logger.Warn("Watch scanner block error", zap.Error(err), zap.Uint64("block", number))
+			}
+			return
+		}
+		err = s.completeBlock(number, deliveries)
+		if err != nil {
+			s.logger.Error("Watch scanner saving state error", zap.Error(err), zap.Uint64("block", number))
+			return
+		}
+		for _, delivery := range deliveries {
+			if s.webhooks.enqueue(ctx, delivery) != nil {
+				return
+			}
+		}
+	}
+}
+
+// completeBlock moves the scan position past number and adds the block's webhooks to the
+// outbox in a single save, so a restart can neither skip nor lose them.
+func (s *watchService) completeBlock(number uint64, deliveries []webhookDelivery) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	previous := s.state.LastScannedBlock
+	if len(deliveries) > 0 && s.state.Outbox == nil {
+		s.state.Outbox = make(map[string]webhookDelivery)
+	}
+	for _, delivery := range deliveries {
+		s.state.Outbox[delivery.Event.Id] = delivery
+	}
+	s.state.LastScannedBlock = &number
+	err := s.saveState()
+	if err != nil {
+		s.state.LastScannedBlock = previous
+		for _, delivery := range deliveries {
+			delete(s.state.Outbox, delivery.Event.Id)
+		}
+	}
+	return err
+}
+
+// resumeOutbox queues the webhooks left undelivered by the previous run.
+func (s *watchService) resumeOutbox(ctx context.Context) {
+	s.mu.Lock()
+	deliveries := make([]webhookDelivery, 0, len(s.state.Outbox))
+	for _, delivery := range s.state.Outbox {
+		deliveries = append(deliveries, delivery)
+	}
+	s.mu.Unlock()
+	sort.Slice(deliveries, func(i, j int) bool {
+		return deliveries[i].Event.BlockNumber < deliveries[j].Event.BlockNumber
+	})
+	if len(deliveries) > 0 {
+		s.logger.Info("Watch scanner resuming webhooks", zap.Int("count", len(deliveries)))
+	}
+	for _, delivery := range deliveries {
+		if s.webhooks.enqueue(ctx, delivery) != nil {
+			return
+		}
+	}
+}
+
+func (s *watchService) setLastScanned(number uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.state.LastScannedBlock = &number
+	err := s.saveState()
+	if err != nil {
+		s.logger.Error("Watch scanner saving state error", zap.Error(err))
+	}
+}
+
+// filterTransferLogs returns the ERC-20 Transfer logs to watched addresses grouped by block.
+func (s *watchService) filterTransferLogs(ctx context.Context, from uint64, to uint64, watched map[common.Address]watchEntry) (map[uint64][]types.Log, error) {
+	recipients := make([]common.Hash, 0, len(watched))
+	for address := range watched {
+		recipients = append(recipients, common.BytesToHash(address.Bytes()))
+	}
+	logs, err := s.client.FilterLogs(ctx, ethereum.FilterQuery{
+		FromBlock: new(big.Int).SetUint64(from),
+		ToBlock:   new(big.Int).SetUint64(to),
+		Topics:    [][]common.Hash{{erc20TransferTopic}, nil, recipients},
+	})
+	if err != nil {
+		return nil, err
+	}
+	byBlock := make(map[uint64][]types.Log)
+	for _, log := range logs {
+		// ERC-721 shares the Transfer signature but indexes the token id as a fourth topic.
+		if log.Removed || len(log.Topics) != 3 || len(log.Data) != 32 {
+			continue
+		}
+		byBlock[log.BlockNumber] = append(byBlock[log.BlockNumber], log)
+	}
+	return byBlock, nil
+}
+
+// scanBlock returns the webhooks for the deposits to watched addresses in block number.
+func (s *watchService) scanBlock(ctx context.Context, number uint64, latest uint64, watched map[common.Address]watchEntry, logs []types.Log) ([]webhookDelivery, error) {
+	block, err := s.client.BlockByNumber(ctx, new(big.Int).SetUint64(number))
+	if err != nil {
+		return nil, err
+	}
+	var deliveries []webhookDelivery
+	confirmations := latest - number + 1
+	signer := types.LatestSignerForChainID(s.chainID)
+
+	for _, tx := range block.Transactions() {
+		if tx.To() == nil || tx.Value().Sign() == 0 {
+			continue
+		}
+		entry, ok := watched[*tx.To()]
+		if !ok {
+			continue
+		}
+		// A reverted transaction does not move its value.
+		receipt, err := s.client.TransactionReceipt(ctx, tx.Hash())
+		if err != nil {
+			return nil, err
+		}
+		if receipt.Status != types.ReceiptStatusSuccessful {
+			continue
+		}
+		event := serializers.WebhookEvent{
+			Id:              tx.Hash().Hex(),
+			Type:            watchEventNative,
+			Address:         tx.To().Hex(),
+			To:              tx.To().Hex(),
+			Value:           tx.Value().String(),
+			FormattedValue:  util.FormatUnits(tx.Value(), util.EtherDecimals),
+			TransactionHash: tx.Hash().Hex(),
+			BlockNumber:     number,
+			BlockHash:       block.Hash().Hex(),
+			Confirmations:   confirmations,
+		}
+		from, err := types.Sender(signer, tx)
+		if err == nil {
+			event.From = from.Hex()
+		}
+		deliveries = append(deliveries, s.delivery(entry, event))
+	}
+
+	for _, log := range logs {
+		to := common.BytesToAddress(log.Topics[2].Bytes())
+		entry, ok := watched[to]
+		if !ok {
+			continue
+		}
+		value := new(big.Int).SetBytes(log.Data)
+		logIndex := log.Index
+		event := serializers.WebhookEvent{
+			Id:              fmt.Sprintf("%s:%d", log.TxHash.Hex(), log.Index),
+			Type:            watchEventErc20,
+			Address:         to.Hex(),
+			From:            common.BytesToAddress(log.Topics[1].Bytes()).Hex(),
+			To:              to.Hex(),
+			Value:           value.String(),
+			Token:           log.Address.Hex(),
+			TransactionHash: log.TxHash.Hex(),
+			LogIndex:        &logIndex,
+			BlockNumber:     number,
+			BlockHash:       log.BlockHash.Hex(),
+			Confirmations:   confirmations,
+		}
+		metadata, err := s.tokens.Get(ctx, log.Address)
+		if err == nil {
+			event.Symbol = metadata.Symbol
+			event.Decimals = &metadata.Decimals
+			event.FormattedValue = util.FormatUnits(value, int(metadata.Decimals))
+		}
+		deliveries = append(deliveries, s.delivery(entry, event))
+	}
+	return deliveries, nil
+}
+
+func (s *watchService) delivery(entry watchEntry, event serializers.WebhookEvent) webhookDelivery {
+	url := entry.WebhookUrl
+	if url == "" {
+		url = s.config.WebhookUrl
+	}
+	s.logger.Info("Watch scanner detected deposit", zap.String("id", event.Id), zap.String("type", event.Type), zap.String("address", event.Address))
+	return webhookDelivery{Url: url, Event: event}
+}
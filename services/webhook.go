@@ -0,0 +1,165 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"go.uber.org/zap"
+	"golang-ethereum-example-api/pkg/logging"
+	"golang-ethereum-example-api/serializers"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	webhookWorkers       = 4
+	webhookQueueSize     = 1024
+	webhookTimeout       = 10 * time.Second
+	webhookMinBackoff    = time.Second
+	webhookMaxBackoff    = 5 * time.Minute
+	webhookSignatureName = "X-Webhook-Signature"
+	webhookTimestampName = "X-Webhook-Timestamp"
+)
+
+type webhookDelivery struct {
+	Url   string                   `json:"url"`
+	Event serializers.WebhookEvent `json:"event"`
+}
+
+// webhookSender POSTs events from a queue so slow receivers never hold up block scanning.
+// Every request is signed with HMAC-SHA256 over "<timestamp>.<body>" and failed deliveries are
+// retried with exponential backoff. done is called once a delivery succeeded or was given up,
+// not for deliveries abandoned by stop.
+type webhookSender struct {
+	client     *http.Client
+	secret     []byte
+	maxRetries int
+	done       func(delivery webhookDelivery)
+	logger     *logging.LogWrapper
+
+	queue  chan webhookDelivery
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func newWebhookSender(secret string, maxRetries int, done func(delivery webhookDelivery), logger *logging.LogWrapper) *webhookSender {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &webhookSender{
+		client:     &http.Client{Timeout: webhookTimeout},
+		secret:     []byte(secret),
+		maxRetries: maxRetries,
+		done:       done,
+		logger:     logger,
+		queue:      make(chan webhookDelivery, webhookQueueSize),
+		ctx:        ctx,
+		cancel:     cancel,
+	}
+}
+
+func (w *webhookSender) start() {
+	for i := 0; i < webhookWorkers; i++ {
+		w.wg.Add(1)
+		go func() {
+			defer w.wg.Done()
+			for {
+				select {
+				case <-w.ctx.Done():
+					return
+				case delivery := <-w.queue:
+					w.deliver(delivery)
+				}
+			}
+		}()
+	}
+}
+
+// stop abandons queued deliveries and pending retries and waits for in flight requests to
+// finish.
+func (w *webhookSender) stop(ctx context.Context) error {
+	w.cancel()
+	done := make(chan struct{})
+	go func() {
+		w.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// enqueue blocks while the queue is full, which slows the scanner down instead of losing events.
+func (w *webhookSender) enqueue(ctx context.Context, delivery webhookDelivery) error {
+	select {
+	case w.queue <- delivery:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (w *webhookSender) deliver(delivery webhookDelivery) {
+	body, err := json.Marshal(delivery.Event)
+	if err != nil {
+		w.logger.Error("Webhook encoding event error", zap.Error(err), zap.String("id", delivery.Event.Id))
+		w.done(delivery)
+		return
+	}
+
+	backoff := webhookMinBackoff
+	for attempt := 0; ; attempt++ {
+		err = w.post(delivery.Url, body)
+		if err == nil {
+			w.done(delivery)
+			return
+		}
+		if w.ctx.Err() != nil {
+			return
+		}
+		if attempt >= w.maxRetries {
+			w.logger.Error("Webhook delivery failed", zap.Error(err), zap.String("id", delivery.Event.Id), zap.String("url", delivery.Url), zap.Int("attempts", attempt+1))
+			w.done(delivery)
+			return
+		}
+		w.logger.Warn("Webhook delivery error, retrying", zap.Error(err), zap.String("id", delivery.Event.Id), zap.Duration("backoff", backoff))
+		select {
+		case <-w.ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, webhookMaxBackoff)
+	}
+}
+
+func (w *webhookSender) post(url string, body []byte) error {
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	mac := hmac.New(sha256.New, w.secret)
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+
+	req, err := http.NewRequestWithContext(w.ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(webhookTimestampName, timestamp)
+	req.Header.Set(webhookSignatureName, "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,9 @@
+package services
+
+import "context"
+
+// Worker is a background job that is started and stopped together with the HTTP server.
+type Worker interface {
+	Start()
+	Stop(ctx context.Context) error
+}